@@ -0,0 +1,57 @@
+package api
+
+import (
+	"PFleetManagement/infrastructure/auth"
+	"PFleetManagement/logic/fleetErrors"
+	"context"
+	"fmt"
+	"github.com/deepmap/oapi-codegen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"net/http"
+	"strings"
+)
+
+// claimsKey is the key of the verified claims of the bearer token in the echo context
+const claimsKey = "auth-claims"
+
+// bearerAuthScheme is the name of the security scheme of the specification which requires a bearer token
+const bearerAuthScheme = "bearerAuth"
+
+// NewAuthenticationFunc creates the function which is called by the OpenAPI validation middleware to check the
+// security requirements of a request. It verifies the bearer token of the request, so that the request can be
+// attributed to the subject of the token. Whether the subject may access the fleets the request refers to is checked
+// by the operations (see operations.NewPermissionChecker).
+func NewAuthenticationFunc(verifier *auth.Verifier) openapi3filter.AuthenticationFunc {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		if input.SecuritySchemeName != bearerAuthScheme {
+			return fmt.Errorf("unsupported security scheme %q", input.SecuritySchemeName)
+		}
+
+		request := input.RequestValidationInput.Request
+		token, ok := bearerToken(request)
+		if !ok {
+			return fmt.Errorf("%w: missing bearer token", fleetErrors.ErrUnauthenticated)
+		}
+
+		claims, err := verifier.Verify(request.Context(), token)
+		if err != nil {
+			return err
+		}
+
+		// the claims are attached to the request by the caller middleware (see NewCallerMiddleware) (the request itself must not be replaced
+		// here, as the validation middleware continues to use it)
+		if echoCtx := middleware.GetEchoContext(ctx); echoCtx != nil {
+			echoCtx.Set(claimsKey, claims)
+		}
+		return nil
+	}
+}
+
+// bearerToken extracts the token from the Authorization header of the given request
+func bearerToken(request *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
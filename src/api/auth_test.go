@@ -0,0 +1,237 @@
+package api
+
+import (
+	"PFleetManagement/infrastructure/auth"
+	"PFleetManagement/logic/caller"
+	"PFleetManagement/logic/model"
+	"PFleetManagement/logic/operations"
+	"PFleetManagement/mocks"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var signingKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+// newAuthTestApp creates an app like the production one which authenticates requests with tokens signed by
+// signingKey (with key ID "test") if authenticated is true and checks the permissions of the caller against the
+// roles in the given database
+func newAuthTestApp(t *testing.T, mockOperations *mocks.MockIOperations, fleetDB *mocks.MockFleetDB,
+	authenticated bool) *echo.Echo {
+
+	e := echo.New()
+	e.HTTPErrorHandler = FleetErrorHandler
+	e.Use(middleware.RequestID())
+
+	var authenticate openapi3filter.AuthenticationFunc
+	if authenticated {
+		verifier := auth.NewVerifier(auth.StaticKeySet{"test": &signingKey.PublicKey}, auth.Config{})
+		authenticate = NewAuthenticationFunc(verifier)
+	}
+	if err := AddOpenApiValidationMiddleware(e, authenticate); err != nil {
+		t.Fatal(err)
+	}
+
+	e.Use(NewCallerMiddleware(!authenticated))
+	RegisterHandlers(e, NewController(operations.NewPermissionChecker(mockOperations, fleetDB)))
+	return e
+}
+
+func newSignedToken(t *testing.T, subject string, fleets ...model.FleetID) string {
+	return newSignedTokenWithClaims(t, jwt.MapClaims{"sub": subject, "fleets": fleets})
+}
+
+func newSignedTokenWithClaims(t *testing.T, claims jwt.MapClaims) string {
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(signingKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func serve(e *echo.Echo, method, target, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthentication_missingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e := newAuthTestApp(t, mocks.NewMockIOperations(ctrl), mocks.NewMockFleetDB(ctrl), true)
+
+	response := serve(e, http.MethodGet, "/fleets/jJd9jb8I", "")
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "Bearer", response.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestAuthentication_invalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e := newAuthTestApp(t, mocks.NewMockIOperations(ctrl), mocks.NewMockFleetDB(ctrl), true)
+
+	response := serve(e, http.MethodGet, "/fleets/jJd9jb8I", newSignedToken(t, "alice", "jJd9jb8I")+"x")
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestAuthentication_unmanagedFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	e := newAuthTestApp(t, mocks.NewMockIOperations(ctrl), mockDB, true)
+
+	mockDB.EXPECT().GetRole(gomock.Any(), "jJd9jb8I", "alice").Return(nil, nil)
+
+	response := serve(e, http.MethodDelete, "/fleets/jJd9jb8I", newSignedToken(t, "alice", "xk48jpgz"))
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestAuthentication_unmanagedTargetFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	e := newAuthTestApp(t, mocks.NewMockIOperations(ctrl), mockDB, true)
+
+	mockDB.EXPECT().GetRole(gomock.Any(), gomock.Any(), "alice").Return(nil, nil).Times(2)
+
+	response := serve(e, http.MethodPost, "/fleets/jJd9jb8I/cars/WVWAA71K08W201030/move?to=xk48jpgz",
+		newSignedToken(t, "alice", "jJd9jb8I"))
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestAuthentication_managedFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	e := newAuthTestApp(t, mockOperations, mockDB, true)
+
+	var info caller.Info
+	mockDB.EXPECT().GetRole(gomock.Any(), "jJd9jb8I", "alice").Return(nil, nil)
+	mockOperations.EXPECT().GetFleet(gomock.Any(), "jJd9jb8I").
+		DoAndReturn(func(ctx context.Context, fleetID model.FleetID) (*model.Fleet, error) {
+			info = caller.FromContext(ctx)
+			return &model.Fleet{FleetID: fleetID}, nil
+		})
+
+	response := serve(e, http.MethodGet, "/fleets/jJd9jb8I", newSignedToken(t, "alice", "jJd9jb8I"))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "alice", info.Actor)
+	assert.Equal(t, []model.FleetID{"jJd9jb8I"}, info.Fleets)
+	assert.Equal(t, response.Header().Get(echo.HeaderXRequestID), info.RequestID)
+}
+
+func TestAuthentication_disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	e := newAuthTestApp(t, mockOperations, mocks.NewMockFleetDB(ctrl), false)
+
+	mockOperations.EXPECT().GetFleet(gomock.Any(), "jJd9jb8I").Return(&model.Fleet{FleetID: "jJd9jb8I"}, nil)
+
+	response := serve(e, http.MethodGet, "/fleets/jJd9jb8I", "")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestAuthentication_requestBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	e := newAuthTestApp(t, mockOperations, mockDB, true)
+
+	name := "Depot"
+	mockDB.EXPECT().GetRole(gomock.Any(), "jJd9jb8I", "alice").Return(nil, nil)
+	mockOperations.EXPECT().UpdateFleet(gomock.Any(), "jJd9jb8I", model.FleetUpdate{Name: &name}).
+		Return(&model.Fleet{FleetID: "jJd9jb8I", Name: name}, nil)
+
+	request := httptest.NewRequest(http.MethodPatch, "/fleets/jJd9jb8I", strings.NewReader(`{"name": "Depot"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(echo.HeaderAuthorization, "Bearer "+newSignedToken(t, "alice", "jJd9jb8I"))
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAuthentication_grantedRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	e := newAuthTestApp(t, mockOperations, mockDB, true)
+
+	viewer := model.RoleViewer
+	mockDB.EXPECT().GetRole(gomock.Any(), "jJd9jb8I", "bob").Return(&viewer, nil).Times(2)
+	mockOperations.EXPECT().GetFleet(gomock.Any(), "jJd9jb8I").Return(&model.Fleet{FleetID: "jJd9jb8I"}, nil)
+
+	token := newSignedToken(t, "bob")
+	readResponse := serve(e, http.MethodGet, "/fleets/jJd9jb8I", token)
+	writeResponse := serve(e, http.MethodDelete, "/fleets/jJd9jb8I", token)
+
+	assert.Equal(t, http.StatusOK, readResponse.Code)
+	assert.Equal(t, http.StatusForbidden, writeResponse.Code)
+}
+
+func TestAuthentication_globalAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	e := newAuthTestApp(t, mockOperations, mocks.NewMockFleetDB(ctrl), true)
+
+	mockOperations.EXPECT().RevokeRole(gomock.Any(), "jJd9jb8I", "bob").Return(nil)
+
+	token := newSignedTokenWithClaims(t, jwt.MapClaims{"sub": "alice", "roles": []string{"admin"}})
+	response := serve(e, http.MethodDelete, "/fleets/jJd9jb8I/members/bob", token)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestAuthentication_skippedPath(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = FleetErrorHandler
+	verifier := auth.NewVerifier(auth.StaticKeySet{"test": &signingKey.PublicKey}, auth.Config{})
+	if err := AddOpenApiValidationMiddleware(e, NewAuthenticationFunc(verifier), "/healthz"); err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/healthz", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	// the path is neither part of the API nor authenticated
+	assert.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/healthz", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(e, http.MethodGet, "/fleets", "").Code)
+}
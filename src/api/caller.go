@@ -0,0 +1,35 @@
+package api
+
+import (
+	"PFleetManagement/infrastructure/auth"
+	"PFleetManagement/logic/caller"
+	"github.com/labstack/echo/v4"
+)
+
+// NewCallerMiddleware creates a middleware which attaches the caller.Info of a request to the context of the request,
+// so that the changes made by the request can be attributed to it. The request ID is read from the X-Request-ID
+// response header, which has to be set by a preceding middleware (e.g. middleware.RequestID). If the request has been
+// authenticated by the OpenAPI validation middleware (see NewAuthenticationFunc), which has to precede this
+// middleware then, the request is attributed to the subject of its token and carries the fleets and roles granted by
+// the token. Requests without a token are only unrestricted if unauthenticated is set, i.e. if requests are
+// deliberately not authenticated at all.
+func NewCallerMiddleware(unauthenticated bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			info := caller.Info{
+				RequestID: ctx.Response().Header().Get(echo.HeaderXRequestID),
+			}
+			if claims, ok := ctx.Get(claimsKey).(*auth.Claims); ok {
+				info.Actor = claims.Subject
+				info.Fleets = claims.Fleets
+				info.Admin = claims.Admin
+			} else {
+				info.Unrestricted = unauthenticated
+			}
+
+			request := ctx.Request()
+			ctx.SetRequest(request.WithContext(caller.WithInfo(request.Context(), info)))
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"PFleetManagement/logic/caller"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallerMiddleware_requestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(NewCallerMiddleware(false))
+
+	var info caller.Info
+	e.GET("/", func(ctx echo.Context) error {
+		info = caller.FromContext(ctx.Request().Context())
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(echo.HeaderXRequestID, "d0a4aa11")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, caller.Info{RequestID: "d0a4aa11"}, info)
+}
+
+func TestCallerMiddleware_generatedRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(NewCallerMiddleware(false))
+
+	var info caller.Info
+	e.GET("/", func(ctx echo.Context) error {
+		info = caller.FromContext(ctx.Request().Context())
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotEmpty(t, info.RequestID)
+	assert.Equal(t, recorder.Header().Get(echo.HeaderXRequestID), info.RequestID)
+}
+
+func TestCallerMiddleware_unauthenticated(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(NewCallerMiddleware(true))
+
+	var info caller.Info
+	e.GET("/", func(ctx echo.Context) error {
+		info = caller.FromContext(ctx.Request().Context())
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(echo.HeaderXRequestID, "d0a4aa11")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, caller.Info{RequestID: "d0a4aa11", Unrestricted: true}, info)
+}
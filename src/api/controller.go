@@ -9,6 +9,9 @@ import (
 	"net/http"
 )
 
+// NextCursorHeader is the response header containing the cursor of the next page of a paged list
+const NextCursorHeader = "X-Next-Cursor"
+
 // Controller The implementation of the request handlers. Delegates parsed (by ServerInterfaceWrapper)
 // and validated requests to operations.IOperations implementation and writes the operation's return
 // value as json response with the correct response code.
@@ -29,14 +32,177 @@ func extractRequestContext(ctx echo.Context) context.Context {
 	return ctx.Request().Context()
 }
 
-func (c Controller) GetCarsInFleet(ctx echo.Context, fleetID model.FleetIDParam) error {
-	cars, err := c.operations.GetCarsInFleet(extractRequestContext(ctx), fleetID)
+func (c Controller) ListFleets(ctx echo.Context) error {
+	fleets, err := c.operations.ListFleets(extractRequestContext(ctx))
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, fleets)
+}
+
+func (c Controller) CreateFleet(ctx echo.Context) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.CreateFleetJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	fleet, err := c.operations.CreateFleet(extractRequestContext(ctx), body)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, fleet)
+}
+
+func (c Controller) DeleteFleet(ctx echo.Context, fleetID model.FleetIDParam) error {
+	err := c.operations.DeleteFleet(extractRequestContext(ctx), fleetID)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c Controller) UpdateFleet(ctx echo.Context, fleetID model.FleetIDParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.UpdateFleetJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	fleet, err := c.operations.UpdateFleet(extractRequestContext(ctx), fleetID, body)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, fleet)
+}
+
+func (c Controller) GetFleet(ctx echo.Context, fleetID model.FleetIDParam) error {
+	fleet, err := c.operations.GetFleet(extractRequestContext(ctx), fleetID)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, fleet)
+}
+
+func (c Controller) GetFleetHistory(ctx echo.Context, fleetID model.FleetIDParam,
+	params model.GetFleetHistoryParams) error {
+
+	query := model.HistoryQuery{
+		Cursor: params.Cursor,
+		From:   params.From,
+		Limit:  params.Limit,
+		To:     params.To,
+	}
+
+	page, err := c.operations.GetFleetHistory(extractRequestContext(ctx), fleetID, query)
+
+	if err != nil {
+		return err
+	}
+
+	setNextCursorHeader(ctx, page.NextCursor)
+	return ctx.JSON(http.StatusOK, page.Entries)
+}
+
+func (c Controller) GetCarsInFleet(ctx echo.Context, fleetID model.FleetIDParam,
+	params model.GetCarsInFleetParams) error {
+
+	query := model.CarQuery{
+		AsOf:               params.AsOf,
+		Brand:              params.Brand,
+		Cursor:             params.Cursor,
+		Limit:              params.Limit,
+		Model:              params.Model,
+		ProductionDateFrom: params.ProductionDateFrom,
+		ProductionDateTo:   params.ProductionDateTo,
+		Sort:               params.Sort,
+	}
+
+	// if requested, report errors of individual cars in a wrapper object instead of failing
+	if params.TolerateErrors != nil && *params.TolerateErrors {
+		overview, err := c.operations.GetCarsInFleetTolerant(extractRequestContext(ctx), fleetID, query)
+
+		if err != nil {
+			return err
+		}
+
+		setNextCursorHeader(ctx, overview.NextCursor)
+		return ctx.JSON(http.StatusOK, overview)
+	}
+
+	page, err := c.operations.GetCarsInFleet(extractRequestContext(ctx), fleetID, query)
+
+	if err != nil {
+		return err
+	}
+
+	setNextCursorHeader(ctx, page.NextCursor)
+	return ctx.JSON(http.StatusOK, page.Cars)
+}
+
+// setNextCursorHeader announces the cursor of the next page (if there is one) in the X-Next-Cursor header
+func setNextCursorHeader(ctx echo.Context, nextCursor *model.Cursor) {
+	if nextCursor != nil {
+		ctx.Response().Header().Set(NextCursorHeader, *nextCursor)
+	}
+}
+
+func (c Controller) CreateCarInFleet(ctx echo.Context, fleetID model.FleetIDParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.CreateCarInFleetJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	car, err := c.operations.CreateCarInFleet(extractRequestContext(ctx), fleetID, body)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, car)
+}
+
+func (c Controller) BatchAddCars(ctx echo.Context, fleetID model.FleetIDParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.BatchAddCarsJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	result, err := c.operations.AddCarsToFleet(extractRequestContext(ctx), fleetID, body.Vins)
 
 	if err != nil {
 		return err
 	}
 
-	return ctx.JSON(http.StatusOK, cars)
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (c Controller) BatchRemoveCars(ctx echo.Context, fleetID model.FleetIDParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.BatchRemoveCarsJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	result, err := c.operations.RemoveCarsFromFleet(extractRequestContext(ctx), fleetID, body.Vins)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, result)
 }
 
 func (c Controller) RemoveCar(ctx echo.Context, fleetID model.FleetIDParam, vin model.VinParam) error {
@@ -68,3 +234,80 @@ func (c Controller) AddCarToFleet(ctx echo.Context, fleetID model.FleetIDParam,
 
 	return ctx.JSON(http.StatusOK, car)
 }
+
+func (c Controller) MoveCar(ctx echo.Context, fleetID model.FleetIDParam, vin model.VinParam,
+	params model.MoveCarParams) error {
+
+	err := c.operations.MoveCar(extractRequestContext(ctx), fleetID, vin, params.To)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c Controller) DecommissionCar(ctx echo.Context, vin model.VinParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.DecommissionCarJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	err := c.operations.DecommissionCar(extractRequestContext(ctx), vin, body.Reason)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c Controller) ReconcileFleets(ctx echo.Context, params model.ReconcileFleetsParams) error {
+	// only repair the inconsistencies if explicitly requested
+	dryRun := params.DryRun == nil || *params.DryRun
+
+	report, err := c.operations.ReconcileFleets(extractRequestContext(ctx), dryRun)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+func (c Controller) GetMembers(ctx echo.Context, fleetID model.FleetIDParam) error {
+	members, err := c.operations.GetMembers(extractRequestContext(ctx), fleetID)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, members)
+}
+
+func (c Controller) RevokeRole(ctx echo.Context, fleetID model.FleetIDParam, subject model.SubjectParam) error {
+	err := c.operations.RevokeRole(extractRequestContext(ctx), fleetID, subject)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c Controller) GrantRole(ctx echo.Context, fleetID model.FleetIDParam, subject model.SubjectParam) error {
+	// the request body has already been validated against the OpenAPI spec
+	var body model.GrantRoleJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+
+	membership, err := c.operations.GrantRole(extractRequestContext(ctx), fleetID, subject, body.Role)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, membership)
+}
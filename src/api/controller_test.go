@@ -1,14 +1,17 @@
 package api
 
 import (
+	"PFleetManagement/logic/fleetErrors"
 	"PFleetManagement/logic/model"
 	"PFleetManagement/mocks"
 	"context"
 	"errors"
 	openapiTypes "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -58,12 +61,13 @@ func TestController_GetCarsInFleet_success(t *testing.T) {
 	mockOperations := mocks.NewMockIOperations(ctrl)
 
 	mockEchoContext.EXPECT().Request().Return(request)
-	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID).Return(carBaseArray, nil)
+	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID, model.CarQuery{}).
+		Return(&model.CarPage{Cars: carBaseArray}, nil)
 	mockEchoContext.EXPECT().JSON(http.StatusOK, carBaseArray)
 
 	controller := NewController(mockOperations)
 
-	err := controller.GetCarsInFleet(mockEchoContext, validFleetID)
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{})
 
 	assert.Nil(t, err)
 }
@@ -84,11 +88,235 @@ func TestController_GetCarsInFleet_operationsError(t *testing.T) {
 	operationsError := errors.New("operations error")
 
 	mockEchoContext.EXPECT().Request().Return(request)
-	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID).Return(nil, operationsError)
+	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID, model.CarQuery{}).Return(nil, operationsError)
 
 	controller := NewController(mockOperations)
 
-	err := controller.GetCarsInFleet(mockEchoContext, validFleetID)
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{})
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_GetCarsInFleet_tolerateErrors_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	tolerateErrors := true
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	overview := &model.FleetOverview{
+		Cars: []model.CarBase{carBase1},
+		Errors: []model.CarError{
+			{Vin: carBase2.Vin, Message: "car not in domain"},
+		},
+	}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetCarsInFleetTolerant(ctx, validFleetID, model.CarQuery{}).Return(overview, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, overview)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{
+		TolerateErrors: &tolerateErrors,
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestController_GetCarsInFleet_tolerateErrors_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	tolerateErrors := true
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetCarsInFleetTolerant(ctx, validFleetID, model.CarQuery{}).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{
+		TolerateErrors: &tolerateErrors,
+	})
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_GetCarsInFleet_tolerateErrorsFalse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	tolerateErrors := false
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID, model.CarQuery{}).
+		Return(&model.CarPage{Cars: carBaseArray}, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, carBaseArray)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{
+		TolerateErrors: &tolerateErrors,
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestController_GetCarsInFleet_asOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	asOf := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID, model.CarQuery{
+		AsOf: &asOf,
+	}).Return(&model.CarPage{Cars: carBaseArray}, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, carBaseArray)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{
+		AsOf: &asOf,
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestController_GetCarsInFleet_nextPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	limit := 2
+	cursor := "b2Zmc2V0OjI"
+	nextCursor := "b2Zmc2V0OjQ"
+	brand := "Tesla"
+	sortParam := model.CarSortProductionDate
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getCars", nil)
+	recorder := httptest.NewRecorder()
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetCarsInFleet(ctx, validFleetID, model.CarQuery{
+		Brand:  &brand,
+		Cursor: &cursor,
+		Limit:  &limit,
+		Sort:   &sortParam,
+	}).Return(&model.CarPage{Cars: carBaseArray, NextCursor: &nextCursor}, nil)
+	mockEchoContext.EXPECT().Response().Return(echo.NewResponse(recorder, nil))
+	mockEchoContext.EXPECT().JSON(http.StatusOK, carBaseArray)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetCarsInFleet(mockEchoContext, validFleetID, model.GetCarsInFleetParams{
+		Brand:  &brand,
+		Cursor: &cursor,
+		Limit:  &limit,
+		Sort:   &sortParam,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, nextCursor, recorder.Header().Get("X-Next-Cursor"))
+}
+
+func TestController_GetFleetHistory_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	limit := 1
+	from := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	nextCursor := "b2Zmc2V0OjE"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getFleetHistory", nil)
+	recorder := httptest.NewRecorder()
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	entries := []model.AuditEntry{{Action: model.AuditFleetCreated, FleetID: validFleetID, Timestamp: from}}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetFleetHistory(ctx, validFleetID, model.HistoryQuery{
+		From:  &from,
+		Limit: &limit,
+	}).Return(&model.HistoryPage{Entries: entries, NextCursor: &nextCursor}, nil)
+	mockEchoContext.EXPECT().Response().Return(echo.NewResponse(recorder, nil))
+	mockEchoContext.EXPECT().JSON(http.StatusOK, entries)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetFleetHistory(mockEchoContext, validFleetID, model.GetFleetHistoryParams{
+		From:  &from,
+		Limit: &limit,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, nextCursor, recorder.Header().Get("X-Next-Cursor"))
+}
+
+func TestController_GetFleetHistory_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/getFleetHistory", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetFleetHistory(ctx, validFleetID, model.HistoryQuery{}).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetFleetHistory(mockEchoContext, validFleetID, model.GetFleetHistoryParams{})
 
 	assert.ErrorIs(t, err, operationsError)
 }
@@ -299,3 +527,636 @@ func TestController_RemoveCar_operationsError(t *testing.T) {
 
 	assert.ErrorIs(t, err, operationsError)
 }
+
+func TestController_MoveCar_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	validTargetFleetID := "xk48jpgz"
+	validVin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/moveCar", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().MoveCar(ctx, validFleetID, validVin, validTargetFleetID).Return(nil)
+	mockEchoContext.EXPECT().NoContent(http.StatusNoContent)
+
+	controller := NewController(mockOperations)
+
+	err := controller.MoveCar(mockEchoContext, validFleetID, validVin, model.MoveCarParams{To: validTargetFleetID})
+
+	assert.Nil(t, err)
+}
+
+func TestController_MoveCar_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	validTargetFleetID := "xk48jpgz"
+	validVin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/moveCar", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().MoveCar(ctx, validFleetID, validVin, validTargetFleetID).Return(operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.MoveCar(mockEchoContext, validFleetID, validVin, model.MoveCarParams{To: validTargetFleetID})
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_CreateCarInFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/createCarInFleet", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.CreateCarInFleetJSONRequestBody) error {
+		*body = car1
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().CreateCarInFleet(ctx, validFleetID, car1).Return(&carBase1, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusCreated, &carBase1)
+
+	controller := NewController(mockOperations)
+
+	err := controller.CreateCarInFleet(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_CreateCarInFleet_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/createCarInFleet", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).Return(nil)
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().CreateCarInFleet(ctx, validFleetID, gomock.Any()).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.CreateCarInFleet(mockEchoContext, validFleetID)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_DecommissionCar_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/decommissionCar", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.DecommissionCarJSONRequestBody) error {
+		body.Reason = "total loss"
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().DecommissionCar(ctx, car1.Vin, "total loss").Return(nil)
+	mockEchoContext.EXPECT().NoContent(http.StatusNoContent)
+
+	controller := NewController(mockOperations)
+
+	err := controller.DecommissionCar(mockEchoContext, car1.Vin)
+
+	assert.Nil(t, err)
+}
+
+func TestController_DecommissionCar_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/decommissionCar", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).Return(nil)
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().DecommissionCar(ctx, car1.Vin, gomock.Any()).Return(operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.DecommissionCar(mockEchoContext, car1.Vin)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_BatchAddCars_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	vins := []string{"3B7HF13Y81G193584", "invalid"}
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/batchAddCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	result := &model.BatchResult{Results: []model.BatchCarResult{
+		{Vin: vins[0], Outcome: model.BatchAdded},
+		{Vin: vins[1], Outcome: model.BatchInvalid},
+	}}
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.BatchAddCarsJSONRequestBody) error {
+		body.Vins = vins
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().AddCarsToFleet(ctx, validFleetID, vins).Return(result, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, result)
+
+	controller := NewController(mockOperations)
+
+	err := controller.BatchAddCars(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_BatchAddCars_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/batchAddCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).Return(nil)
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().AddCarsToFleet(ctx, validFleetID, gomock.Any()).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.BatchAddCars(mockEchoContext, validFleetID)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_BatchRemoveCars_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	vins := []string{"3B7HF13Y81G193584"}
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/batchRemoveCars", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	result := &model.BatchResult{Results: []model.BatchCarResult{{Vin: vins[0], Outcome: model.BatchRemoved}}}
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.BatchRemoveCarsJSONRequestBody) error {
+		body.Vins = vins
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().RemoveCarsFromFleet(ctx, validFleetID, vins).Return(result, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, result)
+
+	controller := NewController(mockOperations)
+
+	err := controller.BatchRemoveCars(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_ListFleets_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/fleets", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	fleets := []model.Fleet{{FleetID: "jJd9jb8I"}}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().ListFleets(ctx).Return(fleets, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, fleets)
+
+	controller := NewController(mockOperations)
+
+	err := controller.ListFleets(mockEchoContext)
+
+	assert.Nil(t, err)
+}
+
+func TestController_CreateFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/fleets", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	fleet := &model.Fleet{FleetID: validFleetID, Name: "Depot"}
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.CreateFleetJSONRequestBody) error {
+		body.FleetID = validFleetID
+		body.Name = "Depot"
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().CreateFleet(ctx, model.FleetCreation{FleetID: validFleetID, Name: "Depot"}).Return(fleet, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusCreated, fleet)
+
+	controller := NewController(mockOperations)
+
+	err := controller.CreateFleet(mockEchoContext)
+
+	assert.Nil(t, err)
+}
+
+func TestController_CreateFleet_bindError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	bindError := errors.New("bind error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).Return(bindError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.CreateFleet(mockEchoContext)
+
+	assert.ErrorIs(t, err, bindError)
+}
+
+func TestController_CreateFleet_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/fleets", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.CreateFleetJSONRequestBody) error {
+		body.FleetID = validFleetID
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().CreateFleet(ctx, model.FleetCreation{FleetID: validFleetID}).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.CreateFleet(mockEchoContext)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_GetFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/fleets/jJd9jb8I", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	fleet := &model.Fleet{FleetID: validFleetID}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetFleet(ctx, validFleetID).Return(fleet, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, fleet)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetFleet(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_UpdateFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+	name := "Depot"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "PATCH", "https://example.com/fleets/jJd9jb8I", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	fleet := &model.Fleet{FleetID: validFleetID, Name: name}
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.UpdateFleetJSONRequestBody) error {
+		body.Name = &name
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().UpdateFleet(ctx, validFleetID, model.FleetUpdate{Name: &name}).Return(fleet, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, fleet)
+
+	controller := NewController(mockOperations)
+
+	err := controller.UpdateFleet(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_UpdateFleet_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "PATCH", "https://example.com/fleets/jJd9jb8I", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).Return(nil)
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().UpdateFleet(ctx, validFleetID, model.FleetUpdate{}).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.UpdateFleet(mockEchoContext, validFleetID)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_DeleteFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "DELETE", "https://example.com/fleets/jJd9jb8I", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().DeleteFleet(ctx, validFleetID).Return(nil)
+	mockEchoContext.EXPECT().NoContent(http.StatusNoContent)
+
+	controller := NewController(mockOperations)
+
+	err := controller.DeleteFleet(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_DeleteFleet_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "DELETE", "https://example.com/fleets/jJd9jb8I", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().DeleteFleet(ctx, validFleetID).Return(operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.DeleteFleet(mockEchoContext, validFleetID)
+
+	assert.ErrorIs(t, err, operationsError)
+}
+
+func TestController_GetMembers_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/fleets/jJd9jb8I/members", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	members := []model.Membership{{Role: model.RoleAdmin, Subject: "alice"}, {Role: model.RoleViewer, Subject: "bob"}}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GetMembers(ctx, validFleetID).Return(members, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, members)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GetMembers(mockEchoContext, validFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestController_GrantRole_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "PUT", "https://example.com/fleets/jJd9jb8I/members/bob", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	membership := &model.Membership{Role: model.RoleManager, Subject: "bob"}
+
+	mockEchoContext.EXPECT().Bind(gomock.Any()).DoAndReturn(func(body *model.GrantRoleJSONRequestBody) error {
+		body.Role = model.RoleManager
+		return nil
+	})
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().GrantRole(ctx, validFleetID, "bob", model.RoleManager).Return(membership, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, membership)
+
+	controller := NewController(mockOperations)
+
+	err := controller.GrantRole(mockEchoContext, validFleetID, "bob")
+
+	assert.Nil(t, err)
+}
+
+func TestController_RevokeRole_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "DELETE", "https://example.com/fleets/jJd9jb8I/members/bob", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().RevokeRole(ctx, validFleetID, "bob").Return(nil)
+	mockEchoContext.EXPECT().NoContent(http.StatusNoContent)
+
+	controller := NewController(mockOperations)
+
+	err := controller.RevokeRole(mockEchoContext, validFleetID, "bob")
+
+	assert.Nil(t, err)
+}
+
+func TestController_RevokeRole_operationsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validFleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "DELETE", "https://example.com/fleets/jJd9jb8I/members/bob", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().RevokeRole(ctx, validFleetID, "bob").Return(fleetErrors.ErrMembershipNotFound)
+
+	controller := NewController(mockOperations)
+
+	err := controller.RevokeRole(mockEchoContext, validFleetID, "bob")
+
+	assert.ErrorIs(t, err, fleetErrors.ErrMembershipNotFound)
+}
+
+func TestController_ReconcileFleets_dryRunByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/reconcileFleets", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	report := &model.ReconciliationReport{DanglingCars: []model.DanglingCar{}, DryRun: true}
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().ReconcileFleets(ctx, true).Return(report, nil)
+	mockEchoContext.EXPECT().JSON(http.StatusOK, report)
+
+	controller := NewController(mockOperations)
+
+	err := controller.ReconcileFleets(mockEchoContext, model.ReconcileFleetsParams{})
+
+	assert.Nil(t, err)
+}
+
+func TestController_ReconcileFleets_prune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	request, _ := http.NewRequestWithContext(ctx, "POST", "https://example.com/reconcileFleets", nil)
+
+	mockEchoContext := mocks.NewMockContext(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	operationsError := errors.New("operations error")
+	dryRun := false
+
+	mockEchoContext.EXPECT().Request().Return(request)
+	mockOperations.EXPECT().ReconcileFleets(ctx, false).Return(nil, operationsError)
+
+	controller := NewController(mockOperations)
+
+	err := controller.ReconcileFleets(mockEchoContext, model.ReconcileFleetsParams{DryRun: &dryRun})
+
+	assert.ErrorIs(t, err, operationsError)
+}
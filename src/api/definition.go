@@ -12,9 +12,36 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// ListFleets Get All Fleets
+	// (GET /fleets)
+	ListFleets(ctx echo.Context) error
+	// CreateFleet Create a New Empty Fleet
+	// (POST /fleets)
+	CreateFleet(ctx echo.Context) error
+	// DeleteFleet Delete the Fleet Including All of Its Car Assignments
+	// (DELETE /fleets/{fleetID})
+	DeleteFleet(ctx echo.Context, fleetID model.FleetIDParam) error
+	// GetFleet Get the Fleet With the Given Fleet ID
+	// (GET /fleets/{fleetID})
+	GetFleet(ctx echo.Context, fleetID model.FleetIDParam) error
+	// UpdateFleet Change the Descriptive Data of the Fleet
+	// (PATCH /fleets/{fleetID})
+	UpdateFleet(ctx echo.Context, fleetID model.FleetIDParam) error
+	// GetFleetHistory Get the Recorded Changes of the Fleet
+	// (GET /fleets/{fleetID}/history)
+	GetFleetHistory(ctx echo.Context, fleetID model.FleetIDParam, params model.GetFleetHistoryParams) error
 	// GetCarsInFleet Get Overview of All Cars Assigned to the Given Fleet
 	// (GET /fleets/{fleetID}/cars)
-	GetCarsInFleet(ctx echo.Context, fleetID model.FleetIDParam) error
+	GetCarsInFleet(ctx echo.Context, fleetID model.FleetIDParam, params model.GetCarsInFleetParams) error
+	// CreateCarInFleet Create a New Car and Add It to the Fleet
+	// (POST /fleets/{fleetID}/cars)
+	CreateCarInFleet(ctx echo.Context, fleetID model.FleetIDParam) error
+	// BatchAddCars Add Multiple Cars to the Fleet
+	// (POST /fleets/{fleetID}/cars:batchAdd)
+	BatchAddCars(ctx echo.Context, fleetID model.FleetIDParam) error
+	// BatchRemoveCars Remove Multiple Cars From the Fleet
+	// (POST /fleets/{fleetID}/cars:batchRemove)
+	BatchRemoveCars(ctx echo.Context, fleetID model.FleetIDParam) error
 	// RemoveCar Remove Car From Fleet
 	// (DELETE /fleets/{fleetID}/cars/{vin})
 	RemoveCar(ctx echo.Context, fleetID model.FleetIDParam, vin model.VinParam) error
@@ -24,6 +51,24 @@ type ServerInterface interface {
 	// AddCarToFleet Add a Car to the Fleet
 	// (PUT /fleets/{fleetID}/cars/{vin})
 	AddCarToFleet(ctx echo.Context, fleetID model.FleetIDParam, vin model.VinParam) error
+	// MoveCar Move a Car to Another Fleet
+	// (POST /fleets/{fleetID}/cars/{vin}/move)
+	MoveCar(ctx echo.Context, fleetID model.FleetIDParam, vin model.VinParam, params model.MoveCarParams) error
+	// GetMembers Get the Roles of All Users in the Fleet
+	// (GET /fleets/{fleetID}/members)
+	GetMembers(ctx echo.Context, fleetID model.FleetIDParam) error
+	// RevokeRole Revoke the Role of the User in the Fleet
+	// (DELETE /fleets/{fleetID}/members/{subject})
+	RevokeRole(ctx echo.Context, fleetID model.FleetIDParam, subject model.SubjectParam) error
+	// GrantRole Grant a Role in the Fleet to the User
+	// (PUT /fleets/{fleetID}/members/{subject})
+	GrantRole(ctx echo.Context, fleetID model.FleetIDParam, subject model.SubjectParam) error
+	// DecommissionCar Decommission a Car
+	// (POST /cars/{vin}/decommission)
+	DecommissionCar(ctx echo.Context, vin model.VinParam) error
+	// ReconcileFleets Check the Cars of All Fleets Against the Car Service
+	// (POST /admin/reconciliation)
+	ReconcileFleets(ctx echo.Context, params model.ReconcileFleetsParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -31,6 +76,118 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// ListFleets converts echo context to params.
+func (w *ServerInterfaceWrapper) ListFleets(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListFleets(ctx)
+	return err
+}
+
+// CreateFleet converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateFleet(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateFleet(ctx)
+	return err
+}
+
+// DeleteFleet converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteFleet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteFleet(ctx, fleetID)
+	return err
+}
+
+// GetFleet converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFleet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFleet(ctx, fleetID)
+	return err
+}
+
+// UpdateFleet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateFleet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateFleet(ctx, fleetID)
+	return err
+}
+
+// GetFleetHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFleetHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.GetFleetHistoryParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFleetHistory(ctx, fleetID, params)
+	return err
+}
+
 // GetCarsInFleet converts echo context to params.
 func (w *ServerInterfaceWrapper) GetCarsInFleet(ctx echo.Context) error {
 	var err error
@@ -42,8 +199,121 @@ func (w *ServerInterfaceWrapper) GetCarsInFleet(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.GetCarsInFleetParams
+	// ------------- Optional query parameter "tolerateErrors" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tolerateErrors", ctx.QueryParams(), &params.TolerateErrors)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tolerateErrors: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "brand" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "brand", ctx.QueryParams(), &params.Brand)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter brand: %s", err))
+	}
+
+	// ------------- Optional query parameter "model" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "model", ctx.QueryParams(), &params.Model)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter model: %s", err))
+	}
+
+	// ------------- Optional query parameter "productionDateFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "productionDateFrom", ctx.QueryParams(), &params.ProductionDateFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productionDateFrom: %s", err))
+	}
+
+	// ------------- Optional query parameter "productionDateTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "productionDateTo", ctx.QueryParams(), &params.ProductionDateTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productionDateTo: %s", err))
+	}
+
+	// ------------- Optional query parameter "asOf" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "asOf", ctx.QueryParams(), &params.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter asOf: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCarsInFleet(ctx, fleetID, params)
+	return err
+}
+
+// CreateCarInFleet converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCarInFleet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateCarInFleet(ctx, fleetID)
+	return err
+}
+
+// BatchAddCars converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchAddCars(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.BatchAddCars(ctx, fleetID)
+	return err
+}
+
+// BatchRemoveCars converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchRemoveCars(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetCarsInFleet(ctx, fleetID)
+	err = w.Handler.BatchRemoveCars(ctx, fleetID)
 	return err
 }
 
@@ -119,6 +389,137 @@ func (w *ServerInterfaceWrapper) AddCarToFleet(ctx echo.Context) error {
 	return err
 }
 
+// MoveCar converts echo context to params.
+func (w *ServerInterfaceWrapper) MoveCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// ------------- Path parameter "vin" -------------
+	var vin model.VinParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "vin", runtime.ParamLocationPath, ctx.Param("vin"), &vin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter vin: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.MoveCarParams
+	// ------------- Required query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.MoveCar(ctx, fleetID, vin, params)
+	return err
+}
+
+// GetMembers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMembers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMembers(ctx, fleetID)
+	return err
+}
+
+// RevokeRole converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeRole(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject model.SubjectParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "subject", runtime.ParamLocationPath, ctx.Param("subject"), &subject)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter subject: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RevokeRole(ctx, fleetID, subject)
+	return err
+}
+
+// GrantRole converts echo context to params.
+func (w *ServerInterfaceWrapper) GrantRole(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "fleetID" -------------
+	var fleetID model.FleetIDParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "fleetID", runtime.ParamLocationPath, ctx.Param("fleetID"), &fleetID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fleetID: %s", err))
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject model.SubjectParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "subject", runtime.ParamLocationPath, ctx.Param("subject"), &subject)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter subject: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GrantRole(ctx, fleetID, subject)
+	return err
+}
+
+// DecommissionCar converts echo context to params.
+func (w *ServerInterfaceWrapper) DecommissionCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "vin" -------------
+	var vin model.VinParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "vin", runtime.ParamLocationPath, ctx.Param("vin"), &vin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter vin: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DecommissionCar(ctx, vin)
+	return err
+}
+
+// ReconcileFleets converts echo context to params.
+func (w *ServerInterfaceWrapper) ReconcileFleets(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.ReconcileFleetsParams
+	// ------------- Optional query parameter "dryRun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dryRun", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dryRun: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ReconcileFleets(ctx, params)
+	return err
+}
+
 // EchoRouter
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
@@ -149,9 +550,25 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/fleets", wrapper.ListFleets)
+	router.POST(baseURL+"/fleets", wrapper.CreateFleet)
+	router.DELETE(baseURL+"/fleets/:fleetID", wrapper.DeleteFleet)
+	router.GET(baseURL+"/fleets/:fleetID", wrapper.GetFleet)
+	router.PATCH(baseURL+"/fleets/:fleetID", wrapper.UpdateFleet)
+	router.GET(baseURL+"/fleets/:fleetID/history", wrapper.GetFleetHistory)
 	router.GET(baseURL+"/fleets/:fleetID/cars", wrapper.GetCarsInFleet)
+	router.POST(baseURL+"/fleets/:fleetID/cars", wrapper.CreateCarInFleet)
+	// (the colon of the custom methods is escaped as it would start a path parameter otherwise)
+	router.POST(baseURL+"/fleets/:fleetID/cars\\:batchAdd", wrapper.BatchAddCars)
+	router.POST(baseURL+"/fleets/:fleetID/cars\\:batchRemove", wrapper.BatchRemoveCars)
 	router.DELETE(baseURL+"/fleets/:fleetID/cars/:vin", wrapper.RemoveCar)
 	router.GET(baseURL+"/fleets/:fleetID/cars/:vin", wrapper.GetCar)
 	router.PUT(baseURL+"/fleets/:fleetID/cars/:vin", wrapper.AddCarToFleet)
+	router.POST(baseURL+"/fleets/:fleetID/cars/:vin/move", wrapper.MoveCar)
+	router.GET(baseURL+"/fleets/:fleetID/members", wrapper.GetMembers)
+	router.DELETE(baseURL+"/fleets/:fleetID/members/:subject", wrapper.RevokeRole)
+	router.PUT(baseURL+"/fleets/:fleetID/members/:subject", wrapper.GrantRole)
+	router.POST(baseURL+"/cars/:vin/decommission", wrapper.DecommissionCar)
+	router.POST(baseURL+"/admin/reconciliation", wrapper.ReconcileFleets)
 
 }
@@ -2,26 +2,107 @@ package api
 
 import (
 	"PFleetManagement/logic/fleetErrors"
+	"encoding/json"
 	"errors"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strings"
 )
 
-type errorContent struct {
-	Message string `json:"message"`
+// MIMEApplicationProblemJSON is the content type of the problem details of failed requests (see RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// The codes of the problems which are not caused by one of the errors of logic/errors
+const (
+	// codeInternalError is the code of unexpected errors, e.g. returned from library calls
+	codeInternalError = "INTERNAL_ERROR"
+)
+
+// problem describes why a request failed (see RFC 7807). The type is always "about:blank", so the title is the
+// standard text of the status code, while the code identifies the problem for machines and never changes.
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// problemType is the status code and the problem code an error of logic/errors is responded with
+type problemType struct {
+	err    error
+	status int
+	code   string
 }
 
-func messageResponse(ctx echo.Context, code int, message string) {
+// problemTypes maps the errors returned by the model (i.e. errors from logic/errors) to responses. The first entry
+// the returned error matches is used.
+var problemTypes = []problemType{
+	// "... not found" errors result in a 404 response
+	{fleetErrors.ErrFleetNotFound, http.StatusNotFound, "FLEET_NOT_FOUND"},
+	{fleetErrors.ErrCarNotFound, http.StatusNotFound, "CAR_NOT_FOUND"},
+	{fleetErrors.ErrCarNotInFleet, http.StatusNotFound, "CAR_NOT_IN_FLEET"},
+	{fleetErrors.ErrMembershipNotFound, http.StatusNotFound, "MEMBERSHIP_NOT_FOUND"},
+
+	// creating a fleet or a car with an ID that is already taken conflicts with the existing one, just like assigning
+	// a car which is already assigned to another fleet
+	{fleetErrors.ErrFleetAlreadyExists, http.StatusConflict, "FLEET_ALREADY_EXISTS"},
+	{fleetErrors.ErrCarAlreadyExists, http.StatusConflict, "CAR_ALREADY_EXISTS"},
+
+	// cars which are (about to be) rented cannot be decommissioned
+	{fleetErrors.ErrCarRented, http.StatusConflict, "CAR_RENTED"},
+	{fleetErrors.ErrCarAssignedToOtherFleet, http.StatusConflict, "CAR_ASSIGNED_TO_OTHER_FLEET"},
+
+	// [logic/errors.ErrCarAlreadyInFleet] is not considered a failure (b/c of idempotency)
+	{fleetErrors.ErrCarAlreadyInFleet, http.StatusNoContent, "CAR_ALREADY_IN_FLEET"},
+
+	// invalid fleet id, vin or cursor, being an invalid/bad request, results in 400
+	{fleetErrors.ErrInvalidFleetId, http.StatusBadRequest, "INVALID_FLEET_ID"},
+	{fleetErrors.ErrInvalidVin, http.StatusBadRequest, "INVALID_VIN"},
+	{fleetErrors.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+
+	// requests without a valid bearer token have to authenticate first
+	{fleetErrors.ErrUnauthenticated, http.StatusUnauthorized, "UNAUTHENTICATED"},
+
+	// authenticated requests for fleets in which the caller does not have the required role are refused
+	{fleetErrors.ErrForbidden, http.StatusForbidden, "FORBIDDEN"},
+
+	// if a downstream service is considered unavailable, the request cannot be processed at the moment
+	{fleetErrors.ErrServiceUnavailable, http.StatusServiceUnavailable, "DOMAIN_UNAVAILABLE"},
+
+	// unexpected responses of the downstream services are internal errors, which are logged
+	{fleetErrors.ErrDomainAssertion, http.StatusInternalServerError, "DOMAIN_ASSERTION"},
+	{fleetErrors.ErrRentalManagementAssertion, http.StatusInternalServerError, "RENTAL_MANAGEMENT_ASSERTION"},
+}
+
+// statusCode derives the problem code of an echo.HTTPError from its status code, e.g. "METHOD_NOT_ALLOWED"
+func statusCode(status int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+func problemResponse(ctx echo.Context, status int, code string, detail string) {
 	var err error
 
-	// HTTP responses to HEAD requests must not include a body
-	if ctx.Request().Method == http.MethodHead {
-		err = ctx.NoContent(code)
+	// HTTP responses to HEAD requests must not include a body, just like responses with status 204
+	if ctx.Request().Method == http.MethodHead || status == http.StatusNoContent {
+		err = ctx.NoContent(status)
 	} else {
-		// if the request was anything but head, return the message in a json object
-		err = ctx.JSON(code, errorContent{
-			message,
+		// if the request was anything but head, describe the problem in a json object
+		var body []byte
+		body, err = json.Marshal(problem{
+			Type:      "about:blank",
+			Title:     http.StatusText(status),
+			Status:    status,
+			Detail:    detail,
+			Instance:  ctx.Request().URL.Path,
+			Code:      code,
+			RequestID: ctx.Response().Header().Get(echo.HeaderXRequestID),
 		})
+		if err == nil {
+			err = ctx.Blob(status, MIMEApplicationProblemJSON, body)
+		}
 	}
 
 	// if the response could not be written, log why
@@ -31,31 +112,29 @@ func messageResponse(ctx echo.Context, code int, message string) {
 }
 
 // The FleetErrorHandler maps errors returned by the model (i.e. errors from logic/errors) to HTTP
-// responses describing the error. This includes determining the correct status codes as defined in
-// the specification.
+// responses describing the error as problem details (see RFC 7807) with a stable code. This includes determining
+// the correct status codes as defined in the specification.
 func FleetErrorHandler(err error, ctx echo.Context) {
 	// if we already have a response, don't do anything
 	if ctx.Response().Committed {
 		return
 	}
 
-	// "... not found" errors result in a 404 response
-	if errors.Is(err, fleetErrors.ErrFleetNotFound) || errors.Is(err, fleetErrors.ErrCarNotFound) ||
-		errors.Is(err, fleetErrors.ErrCarNotInFleet) {
-
-		messageResponse(ctx, http.StatusNotFound, err.Error())
-		return
-	}
+	for _, problemType := range problemTypes {
+		if !errors.Is(err, problemType.err) {
+			continue
+		}
 
-	// [logic/errors.ErrCarAlreadyInFleet] is not considered a failure (b/c of idempotency)
-	if errors.Is(err, fleetErrors.ErrCarAlreadyInFleet) {
-		messageResponse(ctx, http.StatusNoContent, err.Error())
-		return
-	}
+		detail := err.Error()
+		switch problemType.status {
+		case http.StatusUnauthorized:
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		case http.StatusInternalServerError:
+			ctx.Logger().Error(err)
+			detail = problemType.err.Error()
+		}
 
-	// invalid fleet id or vin, being an invalid/bad request, results in 400
-	if errors.Is(err, fleetErrors.ErrInvalidFleetId) || errors.Is(err, fleetErrors.ErrInvalidVin) {
-		messageResponse(ctx, http.StatusBadRequest, err.Error())
+		problemResponse(ctx, problemType.status, problemType.code, detail)
 		return
 	}
 
@@ -69,11 +148,11 @@ func FleetErrorHandler(err error, ctx echo.Context) {
 			message = containedMessage
 		}
 
-		messageResponse(ctx, httpErr.Code, message)
+		problemResponse(ctx, httpErr.Code, statusCode(httpErr.Code), message)
 		return
 	}
 
 	// any other error, which was not handled above; this can be any error returned from library calls
 	ctx.Logger().Error(err)
-	messageResponse(ctx, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	problemResponse(ctx, http.StatusInternalServerError, codeInternalError, "")
 }
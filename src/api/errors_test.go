@@ -0,0 +1,108 @@
+package api
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handleError passes the given error of a request with the given method to the FleetErrorHandler and returns the
+// response
+func handleError(err error, method string) *httptest.ResponseRecorder {
+	e := echo.New()
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(method, "/fleets/xk48jpgz/cars/WVWAA71K08W201030", nil), recorder)
+	ctx.Response().Header().Set(echo.HeaderXRequestID, "b3Ncx4VgPBL1smVCxMTz8ovNuWOKGNRv")
+	FleetErrorHandler(err, ctx)
+	return recorder
+}
+
+// decodeProblem decodes the problem details of the given response
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) problem {
+	assert.Equal(t, MIMEApplicationProblemJSON, recorder.Header().Get(echo.HeaderContentType))
+	var decoded problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestFleetErrorHandler_fleetError(t *testing.T) {
+	recorder := handleError(fmt.Errorf("%w: xk48jpgz", fleetErrors.ErrCarNotInFleet), http.MethodGet)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, problem{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "car not in fleet: xk48jpgz",
+		Instance:  "/fleets/xk48jpgz/cars/WVWAA71K08W201030",
+		Code:      "CAR_NOT_IN_FLEET",
+		RequestID: "b3Ncx4VgPBL1smVCxMTz8ovNuWOKGNRv",
+	}, decodeProblem(t, recorder))
+}
+
+func TestFleetErrorHandler_codes(t *testing.T) {
+	for _, test := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fleetErrors.ErrFleetNotFound, http.StatusNotFound, "FLEET_NOT_FOUND"},
+		{fleetErrors.ErrFleetAlreadyExists, http.StatusConflict, "FLEET_ALREADY_EXISTS"},
+		{fleetErrors.ErrCarAlreadyExists, http.StatusConflict, "CAR_ALREADY_EXISTS"},
+		{fleetErrors.ErrCarRented, http.StatusConflict, "CAR_RENTED"},
+		{fleetErrors.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+		{fleetErrors.ErrForbidden, http.StatusForbidden, "FORBIDDEN"},
+		{fleetErrors.ErrServiceUnavailable, http.StatusServiceUnavailable, "DOMAIN_UNAVAILABLE"},
+		{echo.NewHTTPError(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+		{echo.NewHTTPError(http.StatusBadRequest, "invalid body"), http.StatusBadRequest, "BAD_REQUEST"},
+	} {
+		recorder := handleError(test.err, http.MethodPost)
+
+		assert.Equal(t, test.status, recorder.Code, test.code)
+		decoded := decodeProblem(t, recorder)
+		assert.Equal(t, test.status, decoded.Status, test.code)
+		assert.Equal(t, test.code, decoded.Code)
+	}
+}
+
+func TestFleetErrorHandler_unauthenticated(t *testing.T) {
+	recorder := handleError(fleetErrors.ErrUnauthenticated, http.MethodGet)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "Bearer", recorder.Header().Get(echo.HeaderWWWAuthenticate))
+	assert.Equal(t, "UNAUTHENTICATED", decodeProblem(t, recorder).Code)
+}
+
+func TestFleetErrorHandler_internalError(t *testing.T) {
+	recorder := handleError(fmt.Errorf("%w: status 418", fleetErrors.ErrDomainAssertion), http.MethodGet)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	decoded := decodeProblem(t, recorder)
+	assert.Equal(t, "DOMAIN_ASSERTION", decoded.Code)
+	// internal details are not exposed
+	assert.Equal(t, fleetErrors.ErrDomainAssertion.Error(), decoded.Detail)
+
+	recorder = handleError(fmt.Errorf("connection reset"), http.MethodGet)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	decoded = decodeProblem(t, recorder)
+	assert.Equal(t, "INTERNAL_ERROR", decoded.Code)
+	assert.Empty(t, decoded.Detail)
+}
+
+func TestFleetErrorHandler_noBody(t *testing.T) {
+	// responses to HEAD requests do not have a body
+	recorder := handleError(fleetErrors.ErrFleetNotFound, http.MethodHead)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	// assigning a car twice is not considered a failure
+	recorder = handleError(fleetErrors.ErrCarAlreadyInFleet, http.MethodPut)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
@@ -2,20 +2,72 @@ package api
 
 import (
 	_ "embed"
+	"errors"
 	"github.com/deepmap/oapi-codegen/pkg/middleware"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
+	"regexp"
 )
 
 //go:embed openapi.yaml
 var openApiData []byte
 
-func AddOpenApiValidationMiddleware(e *echo.Echo) error {
+// AddOpenApiValidationMiddleware validates incoming requests against the OpenAPI spec, including its security
+// requirements, which are checked by the given function. If it is nil, requests are not authenticated at all.
+// Requests to the given paths, which are not part of the API (e.g. the health endpoints), are neither validated nor
+// authenticated.
+func AddOpenApiValidationMiddleware(e *echo.Echo, authenticate openapi3filter.AuthenticationFunc,
+	skippedPaths ...string) error {
+
 	swagger, err := openapi3.NewLoader().LoadFromData(openApiData)
 	if err != nil {
 		return err
 	}
 
-	e.Use(middleware.OapiRequestValidator(swagger))
+	if authenticate == nil {
+		authenticate = openapi3filter.NoopAuthenticationFunc
+	}
+
+	e.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
+		Options: openapi3filter.Options{
+			AuthenticationFunc: authenticate,
+		},
+		ErrorHandler: unwrapSecurityError,
+		Skipper: func(ctx echo.Context) bool {
+			for _, path := range skippedPaths {
+				if ctx.Path() == path {
+					return true
+				}
+			}
+			return false
+		},
+	}))
 	return nil
 }
+
+// VinPattern returns the format of a VIN as defined by the OpenAPI spec, so that VINs which are not validated as part
+// of a request (i.e. the items of a VIN list, which are reported individually) are checked against the same format
+func VinPattern() (*regexp.Regexp, error) {
+	swagger, err := openapi3.NewLoader().LoadFromData(openApiData)
+	if err != nil {
+		return nil, err
+	}
+
+	vin, ok := swagger.Components.Schemas["vin"]
+	if !ok || vin.Value == nil || vin.Value.Pattern == "" {
+		return nil, errors.New("the OpenAPI spec does not define the format of a VIN")
+	}
+	return regexp.Compile(vin.Value.Pattern)
+}
+
+// unwrapSecurityError returns the error of the authentication function instead of the generic 403 error the
+// validation middleware creates for unsatisfied security requirements, so that the FleetErrorHandler can map it
+// to the correct status code
+func unwrapSecurityError(_ echo.Context, err *echo.HTTPError) error {
+	var securityErr *openapi3filter.SecurityRequirementsError
+	if errors.As(err.Internal, &securityErr) && len(securityErr.Errors) > 0 {
+		return securityErr.Errors[0]
+	}
+	return err
+}
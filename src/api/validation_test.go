@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVinPattern(t *testing.T) {
+	pattern, err := VinPattern()
+
+	assert.Nil(t, err)
+	assert.True(t, pattern.MatchString("WVWAA71K08W201030"))
+	// too short, and letters which are not used in VINs
+	assert.False(t, pattern.MatchString("WVWAA71K08W20103"))
+	assert.False(t, pattern.MatchString("WVWAA71K08O201030"))
+}
@@ -3,14 +3,19 @@ package main
 import (
 	"PFleetManagement/environment"
 	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/infrastructure/health"
+	"PFleetManagement/infrastructure/metrics"
+	"PFleetManagement/logic/model"
 	"PFleetManagement/testdata"
 	"PFleetManagement/testhelpers"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/steinfletcher/apitest"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -38,12 +43,12 @@ func (suite *ApiTestSuite) SetupSuite() {
 		suite.handleDbConnectionError(err)
 	}
 
-	suite.app, err = newApp(suite.fleetDB)
+	suite.app, err = newApp(suite.fleetDB, metrics.New())
 	if err != nil {
 		suite.T().Fatal(err.Error())
 	}
 
-	// we need to initially clear the database since by default, an empty fleet is inserted into the database
+	// start with an empty collection in case a previous run with the same prefix left data behind
 	suite.clearCollection()
 }
 
@@ -72,6 +77,98 @@ func (suite *ApiTestSuite) clearCollection() {
 	}
 }
 
+func (suite *ApiTestSuite) addFleet(fleetID model.FleetID) {
+	if _, err := suite.fleetDB.AddFleet(context.Background(), model.FleetCreation{FleetID: fleetID}); err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+// assignCars assigns the given cars to the given fleet directly in the database
+func (suite *ApiTestSuite) assignCars(fleetID model.FleetID, vins ...model.Vin) {
+	for _, vin := range vins {
+		if err := suite.fleetDB.AddCarToFleet(context.Background(), fleetID, vin); err != nil {
+			suite.T().Fatal(err)
+		}
+	}
+}
+
+// expectFleets asserts that the response body is a JSON array of fleets with exactly the given metadata.
+// As the timestamps are generated by the database, it only asserts that they are set.
+func (suite *ApiTestSuite) expectFleets(expected ...model.FleetCreation) apitest.Assert {
+	return func(response *http.Response, _ *http.Request) error {
+		var fleets []model.Fleet
+		if err := json.NewDecoder(response.Body).Decode(&fleets); err != nil {
+			return err
+		}
+		if len(fleets) != len(expected) {
+			return fmt.Errorf("expected %d fleets, got %d", len(expected), len(fleets))
+		}
+		for index := range fleets {
+			if err := assertFleetEqual(expected[index], fleets[index]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// expectFleet asserts that the response body is a single fleet with exactly the given metadata.
+// As the timestamps are generated by the database, it only asserts that they are set.
+func (suite *ApiTestSuite) expectFleet(expected model.FleetCreation) apitest.Assert {
+	return func(response *http.Response, _ *http.Request) error {
+		var fleet model.Fleet
+		if err := json.NewDecoder(response.Body).Decode(&fleet); err != nil {
+			return err
+		}
+		return assertFleetEqual(expected, fleet)
+	}
+}
+
+func assertFleetEqual(expected model.FleetCreation, actual model.Fleet) error {
+	actualMetadata := model.FleetCreation{
+		Description: actual.Description,
+		FleetID:     actual.FleetID,
+		Labels:      actual.Labels,
+		Name:        actual.Name,
+		Owner:       actual.Owner,
+	}
+	if !reflect.DeepEqual(expected, actualMetadata) {
+		return fmt.Errorf("expected fleet %+v, got %+v", expected, actualMetadata)
+	}
+	if actual.CreatedAt.IsZero() || actual.UpdatedAt.Before(actual.CreatedAt) {
+		return fmt.Errorf("invalid timestamps of fleet %s", actual.FleetID)
+	}
+	return nil
+}
+
+// expectHistory asserts that the response body is a JSON array of history entries with exactly the given actions,
+// fleets and cars. As the timestamps are generated by the database, it only asserts that they are set. The request
+// IDs are only compared if expected, as they are generated for requests without an ID.
+func (suite *ApiTestSuite) expectHistory(expected ...model.AuditEntry) apitest.Assert {
+	return func(response *http.Response, _ *http.Request) error {
+		var entries []model.AuditEntry
+		if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+			return err
+		}
+		if len(entries) != len(expected) {
+			return fmt.Errorf("expected %d history entries, got %d", len(expected), len(entries))
+		}
+		for index, entry := range entries {
+			if entry.Timestamp.IsZero() {
+				return fmt.Errorf("history entry %d has no timestamp", index)
+			}
+			entry.Timestamp = time.Time{}
+			if expected[index].RequestID == nil {
+				entry.RequestID = nil
+			}
+			if !reflect.DeepEqual(expected[index], entry) {
+				return fmt.Errorf("expected history entry %+v, got %+v", expected[index], entry)
+			}
+		}
+		return nil
+	}
+}
+
 func (suite *ApiTestSuite) SetupTest() {
 	suite.recordingFormatter = testhelpers.NewRecordingFormatter()
 }
@@ -119,7 +216,11 @@ func (suite *ApiTestSuite) newApiTestWithCarMock() *apitest.APITest {
 }
 
 func (suite *ApiTestSuite) newApiTestWithCarAndRentalMocks() *apitest.APITest {
-	return suite.newApiTestWithMocks(append(suite.newCarMock(), suite.newRentalMock()...))
+	return suite.newApiTestWithMocks(suite.newCarAndRentalMocks())
+}
+
+func (suite *ApiTestSuite) newCarAndRentalMocks() []*apitest.Mock {
+	return append(suite.newCarMock(), suite.newRentalMock()...)
 }
 
 func (suite *ApiTestSuite) newCarMock() []*apitest.Mock {
@@ -147,6 +248,176 @@ func (suite *ApiTestSuite) newRentalMock() []*apitest.Mock {
 	}
 }
 
+func (suite *ApiTestSuite) TestListFleets_successEmpty() {
+	suite.newApiTest().
+		Get("/fleets").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestLiveness() {
+	suite.newApiTest().
+		Get(health.LivenessPath).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"status": "up"}`).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateFleet_success() {
+	suite.newApiTest().
+		Post("/fleets").
+		JSON(`{"fleetID": "` + testdata.FleetId + `"}`).
+		Expect(suite.T()).
+		Status(http.StatusCreated).
+		Assert(suite.expectFleet(model.FleetCreation{FleetID: testdata.FleetId})).
+		End()
+	suite.newApiTest().
+		Get("/fleets").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectFleets(model.FleetCreation{FleetID: testdata.FleetId})).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateFleet_successWithMetadata() {
+	suite.newApiTest().
+		Post("/fleets").
+		JSON(testdata.ExampleFleetCreation).
+		Expect(suite.T()).
+		Status(http.StatusCreated).
+		Assert(suite.expectFleet(testdata.ExampleFleetMetadata)).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectFleet(testdata.ExampleFleetMetadata)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateFleet_invalidFleetId() {
+	suite.newApiTest().
+		Post("/fleets").
+		JSON(`{"fleetID": "abc"}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateFleet_duplicate() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Post("/fleets").
+		JSON(`{"fleetID": "` + testdata.FleetId + `"}`).
+		Expect(suite.T()).
+		Status(http.StatusConflict).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleet_invalidFleetId() {
+	suite.newApiTest().
+		Get("/fleets/abc").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleet_unknownFleet() {
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleet_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectFleet(model.FleetCreation{FleetID: testdata.FleetId})).
+		End()
+}
+
+func (suite *ApiTestSuite) TestUpdateFleet_unknownFleet() {
+	suite.newApiTest().
+		Patch("/fleets/" + testdata.FleetId).
+		JSON(`{"name": "Depot"}`).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestUpdateFleet_invalidBody() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Patch("/fleets/" + testdata.FleetId).
+		JSON(`{"labels": {"region": 42}}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestUpdateFleet_success() {
+	suite.newApiTest().
+		Post("/fleets").
+		JSON(testdata.ExampleFleetCreation).
+		Expect(suite.T()).
+		Status(http.StatusCreated).
+		End()
+
+	// only the given properties are replaced
+	expected := testdata.ExampleFleetMetadata
+	expected.Name = "Depot"
+	expected.Labels = map[string]string{"costCenter": "0815"}
+
+	suite.newApiTest().
+		Patch("/fleets/" + testdata.FleetId).
+		JSON(`{"name": "Depot", "labels": {"costCenter": "0815"}}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectFleet(expected)).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectFleet(expected)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDeleteFleet_unknownFleet() {
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDeleteFleet_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTestWithCarMock().
+		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleCarResponse).
+		End()
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
 func (suite *ApiTestSuite) TestGetCars_invalidFleetId() {
 	suite.newApiTest().
 		Get("/fleets/abc/cars").
@@ -164,9 +435,7 @@ func (suite *ApiTestSuite) TestGetCars_unknownFleet() {
 }
 
 func (suite *ApiTestSuite) TestGetCars_successEmpty() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTest().
 		Get("/fleets/" + testdata.FleetId + "/cars").
 		Expect(suite.T()).
@@ -176,9 +445,7 @@ func (suite *ApiTestSuite) TestGetCars_successEmpty() {
 }
 
 func (suite *ApiTestSuite) TestGetCars_success() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -199,6 +466,169 @@ func (suite *ApiTestSuite) TestGetCars_success() {
 		End()
 }
 
+func (suite *ApiTestSuite) TestGetCars_unknownCarInFleet() {
+	suite.addFleet(testdata.FleetId)
+	// the car is assigned directly in the database to simulate that it was deleted from the Car service
+	if err := suite.fleetDB.AddCarToFleet(context.Background(), testdata.FleetId, testdata.UnknownVin); err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusInternalServerError).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_invalidTolerateErrors() {
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("tolerateErrors", "maybe").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_tolerateErrors() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTestWithCarMock().
+		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleCarResponse).
+		End()
+	// the car is assigned directly in the database to simulate that it was deleted from the Car service
+	if err := suite.fleetDB.AddCarToFleet(context.Background(), testdata.FleetId, testdata.UnknownVin); err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("tolerateErrors", "true").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleFleetOverviewWithErrors).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_pagination() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.VinCar2)
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("limit", "1").
+		Query("sort", "-vin").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Header("X-Next-Cursor", testdata.CursorSecondCar).
+		Body("[" + testdata.ExampleCar2Response + "]").
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("limit", "1").
+		Query("sort", "-vin").
+		Query("cursor", testdata.CursorSecondCar).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		HeaderNotPresent("X-Next-Cursor").
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_filter() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.VinCar2)
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("brand", "Mercedes").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCar2Response + "]").
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("productionDateFrom", "2017-07-01").
+		Query("productionDateTo", "2017-07-31").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_sort() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.VinCar2)
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("sort", "-productionDate").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCar2Response + "," + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_invalidLimit() {
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("limit", "0").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_invalidCursor() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("cursor", "bm90LWEtY3Vyc29y").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_invalidAsOf() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("asOf", "yesterday").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetCars_asOf() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.VinCar2)
+
+	// the timestamps are stored with millisecond precision, so the removal must happen in a later millisecond
+	time.Sleep(2 * time.Millisecond)
+	asOf := time.Now().UTC()
+	time.Sleep(2 * time.Millisecond)
+
+	if err := suite.fleetDB.RemoveCarFromFleet(context.Background(), testdata.FleetId, testdata.VinCar); err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.newApiTestWithCarMock().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("asOf", asOf.Format(time.RFC3339Nano)).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleFleetOverview).
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCar2Response + "]").
+		End()
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/cars").
+		Query("asOf", "2000-01-01T00:00:00Z").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
 func (suite *ApiTestSuite) TestGetCar_invalidFleetId() {
 	suite.newApiTest().
 		Get("/fleets/abc/cars/G1YZ23J9P58034278").
@@ -224,9 +654,7 @@ func (suite *ApiTestSuite) TestGetCar_unknownFleet() {
 }
 
 func (suite *ApiTestSuite) TestGetCar_unknownCar() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTest().
 		Get("/fleets/" + testdata.FleetId + "/cars/" + testdata.UnknownVin).
 		Expect(suite.T()).
@@ -235,12 +663,8 @@ func (suite *ApiTestSuite) TestGetCar_unknownCar() {
 }
 
 func (suite *ApiTestSuite) TestGetCar_CarInOtherFleet() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId2); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId2 + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -255,9 +679,7 @@ func (suite *ApiTestSuite) TestGetCar_CarInOtherFleet() {
 }
 
 func (suite *ApiTestSuite) TestGetCar_success_noRental() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -273,9 +695,7 @@ func (suite *ApiTestSuite) TestGetCar_success_noRental() {
 }
 
 func (suite *ApiTestSuite) TestGetCar_success_rental() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar2).
 		Expect(suite.T()).
@@ -315,9 +735,7 @@ func (suite *ApiTestSuite) TestAddCar_unknownFleet() {
 }
 
 func (suite *ApiTestSuite) TestAddCar_unknownCar() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.UnknownVin).
 		Expect(suite.T()).
@@ -326,9 +744,7 @@ func (suite *ApiTestSuite) TestAddCar_unknownCar() {
 }
 
 func (suite *ApiTestSuite) TestAddCar_duplicate() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -343,9 +759,7 @@ func (suite *ApiTestSuite) TestAddCar_duplicate() {
 }
 
 func (suite *ApiTestSuite) TestAddCar_success() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -354,13 +768,9 @@ func (suite *ApiTestSuite) TestAddCar_success() {
 		End()
 }
 
-func (suite *ApiTestSuite) TestAddCar_successMultipleFleets() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId2); err != nil {
-		suite.T().Fatal(err)
-	}
+func (suite *ApiTestSuite) TestAddCar_assignedToOtherFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -370,8 +780,54 @@ func (suite *ApiTestSuite) TestAddCar_successMultipleFleets() {
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId2 + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
+		Status(http.StatusConflict).
+		End()
+	// the car stays assigned to the first fleet only
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
 		Status(http.StatusOK).
-		Body(testdata.ExampleCarResponse).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId2 + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestAddCar_afterRemovalFromOtherFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTestWithCarMock().
+		Put("/fleets/" + testdata.FleetId2 + "/cars/" + testdata.VinCar).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleCarResponse).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDeleteFleet_releasesCars() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTestWithCarMock().
+		Put("/fleets/" + testdata.FleetId2 + "/cars/" + testdata.VinCar).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(testdata.ExampleCarResponse).
 		End()
 }
 
@@ -400,9 +856,7 @@ func (suite *ApiTestSuite) TestRemoveCar_unknownFleet() {
 }
 
 func (suite *ApiTestSuite) TestRemoveCar_unknownCar() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTest().
 		Delete("/fleets/" + testdata.FleetId + "/cars/" + testdata.UnknownVin).
 		Expect(suite.T()).
@@ -411,12 +865,8 @@ func (suite *ApiTestSuite) TestRemoveCar_unknownCar() {
 }
 
 func (suite *ApiTestSuite) TestRemoveCar_CarInOtherFleet() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId2); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId2 + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -431,9 +881,7 @@ func (suite *ApiTestSuite) TestRemoveCar_CarInOtherFleet() {
 }
 
 func (suite *ApiTestSuite) TestRemoveCar_success() {
-	if err := suite.fleetDB.AddFleet(context.Background(), testdata.FleetId); err != nil {
-		suite.T().Fatal(err)
-	}
+	suite.addFleet(testdata.FleetId)
 	suite.newApiTestWithCarMock().
 		Put("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar).
 		Expect(suite.T()).
@@ -446,3 +894,591 @@ func (suite *ApiTestSuite) TestRemoveCar_success() {
 		Status(http.StatusNoContent).
 		End()
 }
+
+func (suite *ApiTestSuite) TestMoveCar_missingTargetFleet() {
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar + "/move").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestMoveCar_invalidTargetFleet() {
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", "abc").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestMoveCar_unknownTargetFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", testdata.FleetId2).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestMoveCar_carNotInFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId2, testdata.VinCar)
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", testdata.FleetId2).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestMoveCar_sameFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+}
+
+func (suite *ApiTestSuite) TestMoveCar_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", testdata.FleetId2).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId2 + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateCarInFleet_invalidBody() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars").
+		JSON(`{"vin": "` + testdata.VinCar + `"}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateCarInFleet_unknownFleet() {
+	// the car is not created in the Car service, so no mock is required
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars").
+		JSON(testdata.ExampleCar).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateCarInFleet_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTestWithMocks([]*apitest.Mock{
+		apitest.NewMock().
+			Post(environment.GetEnvironment().GetCarServerUrl() + "/cars").
+			RespondWith().Status(http.StatusCreated).JSON(`"` + testdata.VinCar + `"`).End(),
+	}).
+		Post("/fleets/" + testdata.FleetId + "/cars").
+		JSON(testdata.ExampleCar).
+		Expect(suite.T()).
+		Status(http.StatusCreated).
+		Body(testdata.ExampleCarResponse).
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestCreateCarInFleet_carAssignedToOtherFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId2, testdata.VinCar)
+	// the created car is deleted again as it cannot be assigned
+	suite.newApiTestWithMocks([]*apitest.Mock{
+		apitest.NewMock().
+			Post(environment.GetEnvironment().GetCarServerUrl() + "/cars").
+			RespondWith().Status(http.StatusCreated).JSON(`"` + testdata.VinCar + `"`).End(),
+		apitest.NewMock().
+			Delete(environment.GetEnvironment().GetCarServerUrl() + "/cars/" + testdata.VinCar).
+			RespondWith().Status(http.StatusNoContent).End(),
+	}).
+		Post("/fleets/" + testdata.FleetId + "/cars").
+		JSON(testdata.ExampleCar).
+		Expect(suite.T()).
+		Status(http.StatusConflict).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDecommissionCar_invalidBody() {
+	suite.newApiTest().
+		Post("/cars/" + testdata.VinCar + "/decommission").
+		JSON(`{"reason": ""}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDecommissionCar_carRented() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar2)
+	suite.newApiTestWithCarAndRentalMocks().
+		Post("/cars/" + testdata.VinCar2 + "/decommission").
+		JSON(`{"reason": "total loss"}`).
+		Expect(suite.T()).
+		Status(http.StatusConflict).
+		End()
+	// the car stays in its fleet
+	suite.newApiTestWithCarAndRentalMocks().
+		Get("/fleets/" + testdata.FleetId + "/cars/" + testdata.VinCar2).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+}
+
+func (suite *ApiTestSuite) TestDecommissionCar_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTestWithMocks(append(suite.newCarAndRentalMocks(),
+		apitest.NewMock().
+			Delete(environment.GetEnvironment().GetCarServerUrl()+"/cars/"+testdata.VinCar).
+			RespondWith().Status(http.StatusNoContent).End(),
+	)).
+		Post("/cars/" + testdata.VinCar + "/decommission").
+		JSON(`{"reason": "total loss"}`).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+
+	vin := testdata.VinCar
+	reason := "total loss"
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/history").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin},
+			model.AuditEntry{Action: model.AuditCarDecommissioned, FleetID: testdata.FleetId, Vin: &vin,
+				Reason: &reason},
+		)).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
+// newCarListMock mocks the Car service listing the given VINs
+func (suite *ApiTestSuite) newCarListMock(vins ...model.Vin) *apitest.Mock {
+	body, _ := json.Marshal(vins)
+	return apitest.NewMock().
+		Get(environment.GetEnvironment().GetCarServerUrl() + "/cars").
+		RespondWith().Status(http.StatusOK).Body(string(body)).End()
+}
+
+func (suite *ApiTestSuite) TestReconcileFleets_dryRun() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.UnknownVin)
+	suite.newApiTestWithMocks(append(suite.newCarMock(), suite.newCarListMock(testdata.VinCar))).
+		Post("/admin/reconciliation").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"dryRun": true, "checkedFleets": 1, "checkedCars": 2, "danglingCars": [
+			{"fleetId": "` + testdata.FleetId + `", "vin": "` + testdata.UnknownVin + `", "pruned": false}
+		]}`).
+		End()
+	// the fleet has not been changed
+	vin := testdata.VinCar
+	unknownVin := testdata.UnknownVin
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/history").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &unknownVin},
+		)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestReconcileFleets_prune() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.UnknownVin)
+	suite.newApiTestWithMocks(append(suite.newCarMock(), suite.newCarListMock(testdata.VinCar))).
+		Post("/admin/reconciliation").
+		Query("dryRun", "false").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"dryRun": false, "checkedFleets": 1, "checkedCars": 2, "danglingCars": [
+			{"fleetId": "` + testdata.FleetId + `", "vin": "` + testdata.UnknownVin + `", "pruned": true}
+		]}`).
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestDecommissionCar_deletedInCarService() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.UnknownVin)
+	suite.newApiTestWithMocks([]*apitest.Mock{
+		apitest.NewMock().
+			Get(environment.GetEnvironment().GetRentalServerUrl() + "/cars/" + testdata.UnknownVin + "/rentalStatus").
+			RespondWith().Status(http.StatusNoContent).End(),
+		apitest.NewMock().
+			Get(environment.GetEnvironment().GetCarServerUrl() + "/cars/" + testdata.UnknownVin).
+			RespondWith().Status(http.StatusNotFound).End(),
+	}).
+		Post("/cars/" + testdata.UnknownVin + "/decommission").
+		JSON(`{"reason": "total loss"}`).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestBatchAddCars_invalidBody() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchAdd").
+		JSON(`{"vins": []}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestBatchAddCars_unknownFleet() {
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchAdd").
+		JSON(`{"vins": ["` + testdata.VinCar + `"]}`).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestBatchAddCars_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId2, testdata.VinCar2)
+	suite.newApiTestWithCarMock().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchAdd").
+		JSON(`{"vins": ["` + testdata.VinCar + `", "` + testdata.VinCar2 + `", "` + testdata.UnknownVin + `", "abc"]}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"results": [
+			{"vin": "` + testdata.VinCar + `", "outcome": "added"},
+			{"vin": "` + testdata.VinCar2 + `", "outcome": "assignedToOtherFleet"},
+			{"vin": "` + testdata.UnknownVin + `", "outcome": "notFound"},
+			{"vin": "abc", "outcome": "invalid"}
+		]}`).
+		End()
+	suite.newApiTestWithCarMock().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchAdd").
+		JSON(`{"vins": ["` + testdata.VinCar + `"]}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"results": [{"vin": "` + testdata.VinCar + `", "outcome": "alreadyInFleet"}]}`).
+		End()
+	suite.newApiTestWithCarMock().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[" + testdata.ExampleCarResponse + "]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestBatchRemoveCars_unknownFleet() {
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchRemove").
+		JSON(`{"vins": ["` + testdata.VinCar + `"]}`).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestBatchRemoveCars_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.assignCars(testdata.FleetId2, testdata.VinCar2)
+	suite.newApiTest().
+		Post("/fleets/" + testdata.FleetId + "/cars:batchRemove").
+		JSON(`{"vins": ["` + testdata.VinCar + `", "` + testdata.VinCar2 + `", "abc"]}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"results": [
+			{"vin": "` + testdata.VinCar + `", "outcome": "removed"},
+			{"vin": "` + testdata.VinCar2 + `", "outcome": "notInFleet"},
+			{"vin": "abc", "outcome": "invalid"}
+		]}`).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/cars").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_unknownFleet() {
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/history").
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_invalidTimeRange() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/history").
+		Query("from", "yesterday").
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_success() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.newApiTestWithCarMock().
+		Put("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar).
+		Header("X-Request-ID", "add-request").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+	suite.newApiTest().
+		Post("/fleets/"+testdata.FleetId+"/cars/"+testdata.VinCar+"/move").
+		Query("to", testdata.FleetId2).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+
+	vin := testdata.VinCar
+	requestID := "add-request"
+	sourceFleetID := testdata.FleetId
+	targetFleetID := testdata.FleetId2
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/history").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin, RequestID: &requestID},
+			model.AuditEntry{Action: model.AuditCarMovedOut, FleetID: testdata.FleetId, Vin: &vin,
+				OtherFleetID: &targetFleetID},
+		)).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId2 + "/history").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId2},
+			model.AuditEntry{Action: model.AuditCarMovedIn, FleetID: testdata.FleetId2, Vin: &vin,
+				OtherFleetID: &sourceFleetID},
+		)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_pagination() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.VinCar2)
+
+	vin, vin2 := testdata.VinCar, testdata.VinCar2
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/history").
+		Query("limit", "2").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Header("X-Next-Cursor", testdata.CursorThirdEntry).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin},
+		)).
+		End()
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/history").
+		Query("limit", "2").
+		Query("cursor", testdata.CursorThirdEntry).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		HeaderNotPresent("X-Next-Cursor").
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin2},
+		)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_timeRange() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/history").
+		Query("to", "2000-01-01T00:00:00Z").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body("[]").
+		End()
+	suite.newApiTest().
+		Get("/fleets/"+testdata.FleetId+"/history").
+		Query("from", "2000-01-01T00:00:00Z").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+		)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetHistory_deletedFleet() {
+	suite.addFleet(testdata.FleetId)
+	suite.assignCars(testdata.FleetId, testdata.VinCar)
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId).
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+
+	vin := testdata.VinCar
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/history").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Assert(suite.expectHistory(
+			model.AuditEntry{Action: model.AuditFleetCreated, FleetID: testdata.FleetId},
+			model.AuditEntry{Action: model.AuditCarAdded, FleetID: testdata.FleetId, Vin: &vin},
+			model.AuditEntry{Action: model.AuditCarRemoved, FleetID: testdata.FleetId, Vin: &vin},
+			model.AuditEntry{Action: model.AuditFleetDeleted, FleetID: testdata.FleetId},
+		)).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetMembers_unknownFleet() {
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/members").
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGrantRole_invalidRole() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Put("/fleets/" + testdata.FleetId + "/members/alice").
+		JSON(`{"role": "owner"}`).
+		Expect(suite.T()).
+		Status(http.StatusBadRequest).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGrantRole_unknownFleet() {
+	suite.newApiTest().
+		Put("/fleets/" + testdata.FleetId + "/members/alice").
+		JSON(`{"role": "viewer"}`).
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestRevokeRole_unknownMember() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId + "/members/alice").
+		Expect(suite.T()).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func (suite *ApiTestSuite) TestMembers_grantAndRevoke() {
+	suite.addFleet(testdata.FleetId)
+	suite.newApiTest().
+		Put("/fleets/" + testdata.FleetId + "/members/bob").
+		JSON(`{"role": "viewer"}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"subject": "bob", "role": "viewer"}`).
+		End()
+	// granting another role replaces the previous one
+	suite.newApiTest().
+		Put("/fleets/" + testdata.FleetId + "/members/bob").
+		JSON(`{"role": "manager"}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+	suite.newApiTest().
+		Put("/fleets/" + testdata.FleetId + "/members/alice").
+		JSON(`{"role": "admin"}`).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/members").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`[{"subject": "alice", "role": "admin"}, {"subject": "bob", "role": "manager"}]`).
+		End()
+	suite.newApiTest().
+		Delete("/fleets/" + testdata.FleetId + "/members/bob").
+		Expect(suite.T()).
+		Status(http.StatusNoContent).
+		End()
+	suite.newApiTest().
+		Get("/fleets/" + testdata.FleetId + "/members").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`[{"subject": "alice", "role": "admin"}]`).
+		End()
+}
+
+func (suite *ApiTestSuite) TestGetFleetSizes() {
+	suite.addFleet(testdata.FleetId)
+	suite.addFleet(testdata.FleetId2)
+	suite.assignCars(testdata.FleetId, testdata.VinCar, testdata.UnknownVin)
+	if err := suite.fleetDB.RemoveCarFromFleet(context.Background(), testdata.FleetId, testdata.UnknownVin); err != nil {
+		suite.T().Fatal(err)
+	}
+
+	sizes, err := suite.fleetDB.GetFleetSizes(context.Background())
+
+	suite.Nil(err)
+	suite.Equal(map[model.FleetID]int{testdata.FleetId: 1, testdata.FleetId2: 0}, sizes)
+}
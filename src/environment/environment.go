@@ -20,18 +20,39 @@ func SetupTestingEnvironment(carServerUrl string, rentalServerUrl string) {
 	setCarServerUrl(carServerUrl)
 	setRentalServerUrl(rentalServerUrl)
 	environment = readEnvironment()
+	// the tests send requests without bearer tokens
+	environment.unauthenticatedAllowed = true
 }
 
 type Environment struct {
 	mongoDbConnectionString string
 	mongoDbDatabase         string
 	appExposePort           int
+	metricsExposePort       int
+	tracingExporter         string
 	appCollectionPrefix     string
 	carServerUrl            string
 	rentalServerUrl         string
 	requestTimeout          time.Duration
+	readinessTimeout        time.Duration
+	shutdownTimeout         time.Duration
+	carRequestConcurrency   int
+	carCacheStaticTTL       time.Duration
+	carCacheDynamicTTL      time.Duration
+	carCacheSize            int
+	requestRetries          int
+	requestRetryBackoff     time.Duration
+	requestRetryMaxBackoff  time.Duration
+	breakerThreshold        int
+	breakerOpenDuration     time.Duration
 	allowOrigins            []string
+	authKeySet              string
+	authIssuer              string
+	authAudience            string
+	authFleetsClaim         string
+	authRolesClaim          string
 	isLocalSetupMode        bool
+	unauthenticatedAllowed  bool
 }
 
 func (e *Environment) GetMongoDbConnectionString() string {
@@ -46,6 +67,16 @@ func (e *Environment) GetAppExposePort() int {
 	return e.appExposePort
 }
 
+// GetMetricsExposePort returns the port on which the Prometheus metrics are exposed, separately from the API
+func (e *Environment) GetMetricsExposePort() int {
+	return e.metricsExposePort
+}
+
+// GetTracingExporter returns where the spans are exported to ("none", "stdout" or "otlp")
+func (e *Environment) GetTracingExporter() string {
+	return e.tracingExporter
+}
+
 func (e *Environment) GetAppCollectionPrefix() string {
 	return e.appCollectionPrefix
 }
@@ -68,10 +99,87 @@ func (e *Environment) GetRequestTimeout() time.Duration {
 	return e.requestTimeout
 }
 
+// GetReadinessTimeout returns how long the readiness probe waits for each dependency of the service
+func (e *Environment) GetReadinessTimeout() time.Duration {
+	return e.readinessTimeout
+}
+
+// GetShutdownTimeout returns how long the in-flight requests may take to finish when the service is stopped
+func (e *Environment) GetShutdownTimeout() time.Duration {
+	return e.shutdownTimeout
+}
+
+func (e *Environment) GetCarRequestConcurrency() int {
+	return e.carRequestConcurrency
+}
+
+// GetCarCacheStaticTTL returns how long the static data of cars is cached. Zero disables the cache.
+func (e *Environment) GetCarCacheStaticTTL() time.Duration {
+	return e.carCacheStaticTTL
+}
+
+func (e *Environment) GetCarCacheDynamicTTL() time.Duration {
+	return e.carCacheDynamicTTL
+}
+
+func (e *Environment) GetCarCacheSize() int {
+	return e.carCacheSize
+}
+
+func (e *Environment) GetRequestRetries() int {
+	return e.requestRetries
+}
+
+func (e *Environment) GetRequestRetryBackoff() time.Duration {
+	return e.requestRetryBackoff
+}
+
+func (e *Environment) GetRequestRetryMaxBackoff() time.Duration {
+	return e.requestRetryMaxBackoff
+}
+
+// GetCircuitBreakerThreshold returns the number of consecutive failures after which requests to a downstream host
+// are rejected. Zero disables the circuit breakers.
+func (e *Environment) GetCircuitBreakerThreshold() int {
+	return e.breakerThreshold
+}
+
+func (e *Environment) GetCircuitBreakerOpenDuration() time.Duration {
+	return e.breakerOpenDuration
+}
+
 func (e *Environment) GetAllowOrigins() []string {
 	return e.allowOrigins
 }
 
+// GetAuthKeySet returns the URL or the file path of the JWKS to verify bearer tokens with.
+// Empty if requests are not authenticated.
+func (e *Environment) GetAuthKeySet() string {
+	return e.authKeySet
+}
+
+func (e *Environment) GetAuthIssuer() string {
+	return e.authIssuer
+}
+
+func (e *Environment) GetAuthAudience() string {
+	return e.authAudience
+}
+
+func (e *Environment) GetAuthFleetsClaim() string {
+	return e.authFleetsClaim
+}
+
+func (e *Environment) GetAuthRolesClaim() string {
+	return e.authRolesClaim
+}
+
 func (e *Environment) IsLocalSetupMode() bool {
 	return e.isLocalSetupMode
 }
+
+// IsUnauthenticatedAccessAllowed returns whether requests may be served without authentication if no key set is
+// configured, which is only the case in the local setup mode and in tests
+func (e *Environment) IsUnauthenticatedAccessAllowed() bool {
+	return e.unauthenticatedAllowed
+}
@@ -14,16 +14,52 @@ const (
 	envMongoDbConnectionString = "MONGODB_CONNECTION_STRING"
 	envMongoDbDatabase         = "MONGODB_DATABASE_NAME"
 	envAppExposePort           = "FM_EXPOSE_PORT"
+	envMetricsExposePort       = "FM_METRICS_PORT"
+	envTracingExporter         = "FM_TRACING_EXPORTER"
 	envAppCollectionPrefix     = "FM_COLLECTION_PREFIX"
 	envCarServerUrl            = "FM_CAR_SERVER"
 	envRentalServerUrl         = "FM_RENTAL_MANAGEMENT_SERVER"
 	envRequestTimeout          = "FM_REQUEST_TIMEOUT"
+	envReadinessTimeout        = "FM_READINESS_TIMEOUT"
+	envShutdownTimeout         = "FM_SHUTDOWN_TIMEOUT"
+	envCarRequestConcurrency   = "FM_CAR_REQUEST_CONCURRENCY"
+	envCarCacheStaticTTL       = "FM_CAR_CACHE_STATIC_TTL"
+	envCarCacheDynamicTTL      = "FM_CAR_CACHE_DYNAMIC_TTL"
+	envCarCacheSize            = "FM_CAR_CACHE_SIZE"
+	envRequestRetries          = "FM_REQUEST_RETRIES"
+	envRetryBackoff            = "FM_RETRY_BACKOFF"
+	envRetryMaxBackoff         = "FM_RETRY_MAX_BACKOFF"
+	envBreakerThreshold        = "FM_BREAKER_THRESHOLD"
+	envBreakerOpenDuration     = "FM_BREAKER_OPEN_DURATION"
 	envAllowOrigins            = "FM_ALLOW_ORIGINS"
+	envAuthKeySet              = "FM_AUTH_JWKS"
+	envAuthIssuer              = "FM_AUTH_ISSUER"
+	envAuthAudience            = "FM_AUTH_AUDIENCE"
+	envAuthFleetsClaim         = "FM_AUTH_FLEETS_CLAIM"
+	envAuthRolesClaim          = "FM_AUTH_ROLES_CLAIM"
 	envLocalSetupMode          = "FM_LOCAL_SETUP"
 
-	defaultAppExposePort       = 80
-	defaultAppCollectionPrefix = ""
-	defaultRequestTimeout      = 5 * time.Second
+	defaultAppExposePort         = 80
+	defaultMetricsExposePort     = 9090
+	defaultTracingExporter       = "none"
+	defaultAppCollectionPrefix   = ""
+	defaultRequestTimeout        = 5 * time.Second
+	defaultReadinessTimeout      = 2 * time.Second
+	defaultShutdownTimeout       = 10 * time.Second
+	defaultCarRequestConcurrency = 10
+	defaultCarCacheStaticTTL     = time.Hour
+	defaultCarCacheDynamicTTL    = 10 * time.Second
+	defaultCarCacheSize          = 10000
+	defaultRequestRetries        = 2
+	defaultRetryBackoff          = 100 * time.Millisecond
+	defaultRetryMaxBackoff       = 2 * time.Second
+	defaultBreakerThreshold      = 5
+	defaultBreakerOpenDuration   = 30 * time.Second
+	defaultAuthKeySet            = ""
+	defaultAuthIssuer            = ""
+	defaultAuthAudience          = ""
+	defaultAuthFleetsClaim       = "fleets"
+	defaultAuthRolesClaim        = "roles"
 )
 
 var defaultAllowOrigins []string = nil
@@ -70,12 +106,31 @@ func readEnvironmentFromEnv() *Environment {
 		mongoDbConnectionString: getStringEnvVariable(envMongoDbConnectionString, nil),
 		mongoDbDatabase:         getStringEnvVariable(envMongoDbDatabase, nil),
 		appExposePort:           getIntegerEnvVariable(envAppExposePort, ptr(defaultAppExposePort)),
+		metricsExposePort:       getIntegerEnvVariable(envMetricsExposePort, ptr(defaultMetricsExposePort)),
+		tracingExporter:         getStringEnvVariable(envTracingExporter, ptr(defaultTracingExporter)),
 		appCollectionPrefix:     getStringEnvVariable(envAppCollectionPrefix, ptr(defaultAppCollectionPrefix)),
 		carServerUrl:            getStringEnvVariable(envCarServerUrl, nil),
 		rentalServerUrl:         getStringEnvVariable(envRentalServerUrl, nil),
 		requestTimeout:          getDurationEnvVariable(envRequestTimeout, ptr(defaultRequestTimeout)),
+		readinessTimeout:        getDurationEnvVariable(envReadinessTimeout, ptr(defaultReadinessTimeout)),
+		shutdownTimeout:         getDurationEnvVariable(envShutdownTimeout, ptr(defaultShutdownTimeout)),
+		carRequestConcurrency:   getPositiveIntegerEnvVariable(envCarRequestConcurrency, ptr(defaultCarRequestConcurrency)),
+		carCacheStaticTTL:       getDurationEnvVariable(envCarCacheStaticTTL, ptr(defaultCarCacheStaticTTL)),
+		carCacheDynamicTTL:      getDurationEnvVariable(envCarCacheDynamicTTL, ptr(defaultCarCacheDynamicTTL)),
+		carCacheSize:            getPositiveIntegerEnvVariable(envCarCacheSize, ptr(defaultCarCacheSize)),
+		requestRetries:          getNonNegativeIntegerEnvVariable(envRequestRetries, ptr(defaultRequestRetries)),
+		requestRetryBackoff:     getDurationEnvVariable(envRetryBackoff, ptr(defaultRetryBackoff)),
+		requestRetryMaxBackoff:  getDurationEnvVariable(envRetryMaxBackoff, ptr(defaultRetryMaxBackoff)),
+		breakerThreshold:        getNonNegativeIntegerEnvVariable(envBreakerThreshold, ptr(defaultBreakerThreshold)),
+		breakerOpenDuration:     getDurationEnvVariable(envBreakerOpenDuration, ptr(defaultBreakerOpenDuration)),
 		allowOrigins:            getStringArrayEnvVariable(envAllowOrigins, &defaultAllowOrigins),
+		authKeySet:              getStringEnvVariable(envAuthKeySet, ptr(defaultAuthKeySet)),
+		authIssuer:              getStringEnvVariable(envAuthIssuer, ptr(defaultAuthIssuer)),
+		authAudience:            getStringEnvVariable(envAuthAudience, ptr(defaultAuthAudience)),
+		authFleetsClaim:         getStringEnvVariable(envAuthFleetsClaim, ptr(defaultAuthFleetsClaim)),
+		authRolesClaim:          getStringEnvVariable(envAuthRolesClaim, ptr(defaultAuthRolesClaim)),
 		isLocalSetupMode:        getBooleanEnvVariable(envLocalSetupMode),
+		unauthenticatedAllowed:  getBooleanEnvVariable(envLocalSetupMode),
 	}
 }
 
@@ -117,6 +172,28 @@ func getIntegerEnvVariable(variableName string, defaultValue *int) int {
 	return intValue
 }
 
+// getPositiveIntegerEnvVariable returns the integer value of the environment variable with the given name
+// like getIntegerEnvVariable, but additionally panics if the value is not greater than zero.
+func getPositiveIntegerEnvVariable(variableName string, defaultValue *int) int {
+	intValue := getIntegerEnvVariable(variableName, defaultValue)
+	if intValue <= 0 {
+		panic(fmt.Sprintf("Invalid value for positive integer environment variable \"%s\": %d",
+			variableName, intValue))
+	}
+	return intValue
+}
+
+// getNonNegativeIntegerEnvVariable returns the integer value of the environment variable with the given name
+// like getIntegerEnvVariable, but additionally panics if the value is negative.
+func getNonNegativeIntegerEnvVariable(variableName string, defaultValue *int) int {
+	intValue := getIntegerEnvVariable(variableName, defaultValue)
+	if intValue < 0 {
+		panic(fmt.Sprintf("Invalid value for non-negative integer environment variable \"%s\": %d",
+			variableName, intValue))
+	}
+	return intValue
+}
+
 // getBooleanEnvVariable returns the boolean value of the environment variable with the given name.
 // If the environment variable is not set, false is returned.
 // If the environment variable is not a valid boolean value, the program will panic.
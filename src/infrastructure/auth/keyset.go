@@ -0,0 +1,256 @@
+// Package auth verifies the bearer tokens (JWTs) authenticating requests against the public keys of the issuer,
+// which are provided as a JSON Web Key Set (JWKS).
+package auth
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey shows that a key set does not contain a key with the requested ID
+var ErrUnknownKey = errors.New("unknown key")
+
+// minRefreshInterval limits how often a remote key set is fetched again because of tokens signed with unknown keys
+const minRefreshInterval = time.Minute
+
+// KeySet provides the public keys to verify the signatures of tokens, identified by their key ID ("kid")
+type KeySet interface {
+	// Key returns the public key with the given ID or ErrUnknownKey if there is none
+	Key(ctx context.Context, keyID string) (crypto.PublicKey, error)
+}
+
+// StaticKeySet is a KeySet with a fixed set of keys
+type StaticKeySet map[string]crypto.PublicKey
+
+func (s StaticKeySet) Key(_ context.Context, keyID string) (crypto.PublicKey, error) {
+	key, ok := s[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return key, nil
+}
+
+// LoadKeySet loads the key set from the given source, which is either an HTTP(S) URL or the path of a file containing
+// a JWKS. A key set loaded from a URL is fetched again (with the given client) if a token refers to an unknown key,
+// so that keys can be rotated by the issuer. Fails if the key set cannot be loaded initially.
+func LoadKeySet(ctx context.Context, source string, client *http.Client) (KeySet, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		return ParseKeySet(data)
+	}
+
+	remote := &remoteKeySet{url: source, client: client}
+	if err := remote.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// remoteKeySet is a KeySet which is fetched from a URL
+type remoteKeySet struct {
+	url    string
+	client *http.Client
+
+	// concurrent refreshes share a single request
+	refreshes resilience.Coalescer[StaticKeySet]
+
+	// the mutex guards the keys and the time of the last refresh, but is not held while the key set is fetched
+	mutex       sync.Mutex
+	keys        StaticKeySet
+	refreshedAt time.Time
+}
+
+func (r *remoteKeySet) Key(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	r.mutex.Lock()
+	key, ok := r.keys[keyID]
+	refreshDue := time.Since(r.refreshedAt) >= minRefreshInterval
+	r.mutex.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	// the key might have been added by the issuer, but forged tokens must not cause a request each
+	if refreshDue {
+		if err := r.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.keys.Key(ctx, keyID)
+}
+
+// refresh fetches the key set unless it has been refreshed within the minimum refresh interval (e.g. by a concurrent
+// caller). Callers refreshing at the same time wait for the same request.
+func (r *remoteKeySet) refresh(ctx context.Context) error {
+	_, err := r.refreshes.Do(ctx, r.url, func(ctx context.Context) (StaticKeySet, error) {
+		r.mutex.Lock()
+		refreshed := time.Since(r.refreshedAt) < minRefreshInterval
+		r.mutex.Unlock()
+		if refreshed {
+			return nil, nil
+		}
+
+		keys, err := r.fetch(ctx)
+		if ctx.Err() != nil {
+			// the fetch was abandoned by all callers, which says nothing about the issuer
+			return nil, err
+		}
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		// a failed attempt counts as well, so that an unavailable issuer is not queried for every request
+		r.refreshedAt = time.Now()
+		if err != nil {
+			return nil, err
+		}
+		r.keys = keys
+		return keys, nil
+	})
+	return err
+}
+
+// fetch requests the key set from its URL
+func (r *remoteKeySet) fetch(ctx context.Context) (StaticKeySet, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d when fetching key set", response.StatusCode)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeySet(data)
+}
+
+// jsonWebKey is a public key of a JWKS (RFC 7517). Only RSA and elliptic curve keys are supported.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// elliptic curve keys
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+// ParseKeySet reads the signature keys of the given JWKS. Keys of unsupported types or for encryption are skipped.
+func ParseKeySet(data []byte) (StaticKeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	keys := make(StaticKeySet, len(document.Keys))
+	for _, key := range document.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		var publicKey crypto.PublicKey
+		var err error
+		switch key.KeyType {
+		case "RSA":
+			publicKey, err = key.rsaPublicKey()
+		case "EC":
+			publicKey, err = key.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", key.KeyID, err)
+		}
+		keys[key.KeyID] = publicKey
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent too large")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+	}
+
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeBigInt decodes a base64url encoded unsigned big-endian integer as used by JWKs
+func decodeBigInt(encoded string) (*big.Int, error) {
+	if encoded == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
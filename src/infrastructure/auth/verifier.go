@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+// DefaultFleetsClaim is the default name of the claim listing the IDs of the fleets a user manages
+const DefaultFleetsClaim = "fleets"
+
+// DefaultRolesClaim is the default name of the claim listing the global roles of a user
+const DefaultRolesClaim = "roles"
+
+// adminRole is the global role (in the roles claim) of users who may access all fleets
+const adminRole = "admin"
+
+// leeway is the tolerated clock skew between the issuer and this service when checking the validity period of tokens
+const leeway = 30 * time.Second
+
+// Config configures the verification of tokens
+type Config struct {
+	// Issuer is the expected issuer ("iss") of tokens. It is not checked if empty.
+	Issuer string
+
+	// Audience is the expected audience ("aud") of tokens. It is not checked if empty.
+	Audience string
+
+	// FleetsClaim is the name of the claim listing the IDs of the fleets a user manages. Defaults to
+	// DefaultFleetsClaim if empty.
+	FleetsClaim string
+
+	// RolesClaim is the name of the claim listing the global roles of a user. Defaults to DefaultRolesClaim if empty.
+	RolesClaim string
+}
+
+// Claims are the verified claims of a token relevant to this service
+type Claims struct {
+	// Subject identifies the user the token was issued to
+	Subject string
+
+	// Fleets are the IDs of the fleets the user manages (never nil)
+	Fleets []model.FleetID
+
+	// Admin shows that the user has the global admin role
+	Admin bool
+}
+
+// Verifier verifies signed tokens against a KeySet
+type Verifier struct {
+	keys        KeySet
+	parser      *jwt.Parser
+	fleetsClaim string
+	rolesClaim  string
+}
+
+// NewVerifier creates a Verifier accepting tokens signed with a key of the given KeySet (identified by the key ID
+// in the header of the token) using an asymmetric algorithm
+func NewVerifier(keys KeySet, config Config) *Verifier {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	fleetsClaim := config.FleetsClaim
+	if fleetsClaim == "" {
+		fleetsClaim = DefaultFleetsClaim
+	}
+	rolesClaim := config.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = DefaultRolesClaim
+	}
+
+	return &Verifier{
+		keys:        keys,
+		parser:      jwt.NewParser(options...),
+		fleetsClaim: fleetsClaim,
+		rolesClaim:  rolesClaim,
+	}
+}
+
+// Verify checks the signature and the registered claims of the given token and returns its claims.
+// Fails with fleetErrors.ErrUnauthenticated if the token is invalid.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, keyID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", fleetErrors.ErrUnauthenticated, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", fleetErrors.ErrUnauthenticated, err)
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", fleetErrors.ErrUnauthenticated)
+	}
+
+	fleets, err := stringsOf(claims, v.fleetsClaim)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", fleetErrors.ErrUnauthenticated, err)
+	}
+	roles, err := stringsOf(claims, v.rolesClaim)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", fleetErrors.ErrUnauthenticated, err)
+	}
+
+	admin := false
+	for _, role := range roles {
+		admin = admin || role == adminRole
+	}
+
+	return &Claims{
+		Subject: subject,
+		Fleets:  fleets,
+		Admin:   admin,
+	}, nil
+}
+
+// stringsOf reads the given claim, which is an array of strings. A missing (or null) claim is treated like an empty
+// array, e.g. the user does not manage any fleet.
+func stringsOf(claims jwt.MapClaims, name string) ([]string, error) {
+	strings := make([]string, 0)
+
+	value, ok := claims[name]
+	if !ok || value == nil {
+		return strings, nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid type of claim " + name)
+	}
+	for _, value := range values {
+		stringValue, ok := value.(string)
+		if !ok {
+			return nil, errors.New("invalid type of claim " + name)
+		}
+		strings = append(strings, stringValue)
+	}
+	return strings, nil
+}
@@ -0,0 +1,348 @@
+package auth
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+var ecKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+// keySetDocument is a JWKS containing the public parts of rsaKey (as "rsa-1") and ecKey (as "ec-1")
+var keySetDocument = fmt.Sprintf(`{"keys": [
+	{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": "%s", "e": "%s"},
+	{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "%s", "y": "%s"},
+	{"kty": "oct", "kid": "hmac-1", "k": "c2VjcmV0"},
+	{"kty": "RSA", "kid": "rsa-enc", "use": "enc", "n": "%[1]s", "e": "%[2]s"}
+]}`,
+	encodeBigInt(rsaKey.N), encodeBigInt(big.NewInt(int64(rsaKey.E))),
+	encodeBigInt(ecKey.X), encodeBigInt(ecKey.Y))
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func newToken(t *testing.T, method jwt.SigningMethod, keyID string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "alice",
+		"iss":    "https://idp.example.com",
+		"aud":    "fleet-management",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"fleets": []string{"jJd9jb8I", "xk48jpgz"},
+	}
+}
+
+func newTestVerifier(t *testing.T) *Verifier {
+	keys, err := ParseKeySet([]byte(keySetDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewVerifier(keys, Config{
+		Issuer:   "https://idp.example.com",
+		Audience: "fleet-management",
+	})
+}
+
+func TestParseKeySet(t *testing.T) {
+	keys, err := ParseKeySet([]byte(keySetDocument))
+
+	assert.Nil(t, err)
+	assert.Len(t, keys, 2)
+	assert.True(t, rsaKey.PublicKey.Equal(keys["rsa-1"]))
+	assert.True(t, ecKey.PublicKey.Equal(keys["ec-1"]))
+}
+
+func TestParseKeySet_invalidKey(t *testing.T) {
+	_, err := ParseKeySet([]byte(`{"keys": [{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`))
+
+	assert.ErrorContains(t, err, "point not on curve")
+}
+
+func TestVerifier_Verify_rsa(t *testing.T) {
+	verifier := newTestVerifier(t)
+	token := newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &Claims{Subject: "alice", Fleets: []model.FleetID{"jJd9jb8I", "xk48jpgz"}}, claims)
+}
+
+func TestVerifier_Verify_ecdsa(t *testing.T) {
+	verifier := newTestVerifier(t)
+	token := newToken(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims())
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", claims.Subject)
+}
+
+func TestVerifier_Verify_noFleets(t *testing.T) {
+	verifier := newTestVerifier(t)
+	tokenClaims := validClaims()
+	delete(tokenClaims, "fleets")
+	token := newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, tokenClaims)
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.FleetID{}, claims.Fleets)
+}
+
+func TestVerifier_Verify_customFleetsClaim(t *testing.T) {
+	verifier := NewVerifier(StaticKeySet{"rsa-1": &rsaKey.PublicKey}, Config{FleetsClaim: "groups"})
+	tokenClaims := validClaims()
+	tokenClaims["groups"] = []string{"jJd9jb8I"}
+	token := newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, tokenClaims)
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.FleetID{"jJd9jb8I"}, claims.Fleets)
+}
+
+func TestVerifier_Verify_admin(t *testing.T) {
+	verifier := newTestVerifier(t)
+	tokenClaims := validClaims()
+	tokenClaims["roles"] = []string{"auditor", "admin"}
+	token := newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, tokenClaims)
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.True(t, claims.Admin)
+}
+
+func TestVerifier_Verify_customRolesClaim(t *testing.T) {
+	verifier := NewVerifier(StaticKeySet{"rsa-1": &rsaKey.PublicKey}, Config{RolesClaim: "groups"})
+	tokenClaims := validClaims()
+	tokenClaims["roles"] = []string{"admin"}
+	tokenClaims["groups"] = []string{"auditor"}
+	token := newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, tokenClaims)
+
+	claims, err := verifier.Verify(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.False(t, claims.Admin)
+}
+
+func TestVerifier_Verify_invalidTokens(t *testing.T) {
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	withClaim := func(name string, value interface{}) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := map[string]string{
+		"malformed":    "not-a-token",
+		"unknownKey":   newToken(t, jwt.SigningMethodRS256, "rsa-2", rsaKey, validClaims()),
+		"wrongKey":     newToken(t, jwt.SigningMethodRS256, "rsa-1", otherKey, validClaims()),
+		"symmetric":    newToken(t, jwt.SigningMethodHS256, "hmac-1", []byte("secret"), validClaims()),
+		"expired":      newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("exp", time.Now().Add(-time.Hour).Unix())),
+		"noExpiration": newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("exp", nil)),
+		"wrongIssuer":  newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("iss", "https://other.example.com")),
+		"wrongAud":     newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("aud", "other-service")),
+		"noSubject":    newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("sub", nil)),
+		"invalidFleet": newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("fleets", "jJd9jb8I")),
+		"invalidRoles": newToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("roles", []int{1})),
+	}
+
+	verifier := newTestVerifier(t)
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), token)
+
+			assert.ErrorIs(t, err, fleetErrors.ErrUnauthenticated)
+			assert.Nil(t, claims)
+		})
+	}
+}
+
+func TestLoadKeySet_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(keySetDocument), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadKeySet(context.Background(), path, http.DefaultClient)
+	assert.Nil(t, err)
+
+	key, err := keys.Key(context.Background(), "rsa-1")
+	assert.Nil(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+}
+
+func TestLoadKeySet_url(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = writer.Write([]byte(keySetDocument))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL, server.Client())
+	assert.Nil(t, err)
+
+	key, err := keys.Key(context.Background(), "ec-1")
+	assert.Nil(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	// the key set has just been fetched, so unknown keys do not cause another request
+	_, err = keys.Key(context.Background(), "rsa-2")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestLoadKeySet_urlRefreshedForUnknownKey(t *testing.T) {
+	var document atomic.Value
+	document.Store(`{"keys": []}`)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = writer.Write([]byte(document.Load().(string)))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL, server.Client())
+	assert.Nil(t, err)
+
+	// the issuer rotates its keys after the minimum refresh interval
+	document.Store(keySetDocument)
+	keys.(*remoteKeySet).refreshedAt = time.Now().Add(-minRefreshInterval)
+
+	key, err := keys.Key(context.Background(), "rsa-1")
+	assert.Nil(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+}
+
+func TestLoadKeySet_urlRefreshesShared(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		// the initial request is answered immediately, the refresh only when released
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		_, _ = writer.Write([]byte(keySetDocument))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL, server.Client())
+	assert.Nil(t, err)
+	remote := keys.(*remoteKeySet)
+	remote.mutex.Lock()
+	remote.keys = StaticKeySet{"ec-1": remote.keys["ec-1"]}
+	remote.refreshedAt = time.Now().Add(-minRefreshInterval)
+	remote.mutex.Unlock()
+
+	const callers = 5
+	results := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := keys.Key(context.Background(), "rsa-1")
+			results <- err
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		return requests.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	// known keys are still returned while the key set is fetched
+	key, err := keys.Key(context.Background(), "ec-1")
+	assert.Nil(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	close(release)
+	for i := 0; i < callers; i++ {
+		assert.Nil(t, <-results)
+	}
+	// the callers either waited for the same request or found the refreshed key set
+	assert.Equal(t, int32(2), requests.Load())
+
+	// the key set has just been refreshed, so unknown keys do not cause another request
+	_, err = keys.Key(context.Background(), "rsa-2")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestLoadKeySet_urlRefreshCanceled(t *testing.T) {
+	var requests atomic.Int32
+	abandoned := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// the first refresh hangs until it is canceled
+		if requests.Add(1) == 2 {
+			<-request.Context().Done()
+			close(abandoned)
+			return
+		}
+		_, _ = writer.Write([]byte(keySetDocument))
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL, server.Client())
+	assert.Nil(t, err)
+	remote := keys.(*remoteKeySet)
+	remote.mutex.Lock()
+	remote.keys = StaticKeySet{}
+	remote.refreshedAt = time.Now().Add(-minRefreshInterval)
+	remote.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = keys.Key(ctx, "rsa-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// give the abandoned refresh the chance to finish
+	<-abandoned
+	time.Sleep(20 * time.Millisecond)
+
+	// the abandoned refresh does not delay the next one
+	key, err := keys.Key(context.Background(), "rsa-1")
+	assert.Nil(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestLoadKeySet_urlUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	keys, err := LoadKeySet(context.Background(), server.URL, server.Client())
+
+	assert.ErrorContains(t, err, "unexpected status code 500")
+	assert.Nil(t, keys)
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"PFleetManagement/logic/caller"
+	"PFleetManagement/logic/model"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// auditEntry is a recorded change of a fleet. Entries are only ever inserted, never changed or deleted.
+type auditEntry struct {
+	Timestamp    time.Time         `bson:"timestamp"`
+	Action       model.AuditAction `bson:"action"`
+	FleetId      model.FleetID     `bson:"fleetId"`
+	Vin          model.Vin         `bson:"vin,omitempty"`
+	OtherFleetId model.FleetID     `bson:"otherFleetId,omitempty"`
+	Reason       string            `bson:"reason,omitempty"`
+	Actor        string            `bson:"actor,omitempty"`
+	RequestId    string            `bson:"requestId,omitempty"`
+}
+
+func (e *auditEntry) toModel() model.AuditEntry {
+	entry := model.AuditEntry{
+		Action:    e.Action,
+		FleetID:   e.FleetId,
+		Timestamp: e.Timestamp,
+	}
+	if e.Vin != "" {
+		entry.Vin = &e.Vin
+	}
+	if e.OtherFleetId != "" {
+		entry.OtherFleetID = &e.OtherFleetId
+	}
+	if e.Reason != "" {
+		entry.Reason = &e.Reason
+	}
+	if e.Actor != "" {
+		entry.Actor = &e.Actor
+	}
+	if e.RequestId != "" {
+		entry.RequestID = &e.RequestId
+	}
+	return entry
+}
+
+// newAuditEntry creates an entry for the given change at the given time, attributed to the caller of the context
+func newAuditEntry(ctx context.Context, timestamp time.Time, action model.AuditAction,
+	fleetId model.FleetID) auditEntry {
+
+	info := caller.FromContext(ctx)
+	return auditEntry{
+		Timestamp: timestamp,
+		Action:    action,
+		FleetId:   fleetId,
+		Actor:     info.Actor,
+		RequestId: info.RequestID,
+	}
+}
+
+// newCarAuditEntry creates an entry for the given change of the assignment of the given car
+func newCarAuditEntry(ctx context.Context, timestamp time.Time, action model.AuditAction, fleetId model.FleetID,
+	vin model.Vin) auditEntry {
+
+	entry := newAuditEntry(ctx, timestamp, action, fleetId)
+	entry.Vin = vin
+	return entry
+}
+
+// audit appends the given entries to the audit collection. To record a change atomically, it must be called in the
+// same transaction as the change itself.
+func (m *connection) audit(ctx context.Context, entries ...auditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(entries))
+	for index, entry := range entries {
+		documents[index] = entry
+	}
+	_, err := m.database.Collection(m.auditCollection).InsertMany(ctx, documents)
+	return err
+}
+
+func (m *connection) GetFleetHistory(ctx context.Context, fleetId model.FleetID, from, to *time.Time,
+	offset, limit int) ([]model.AuditEntry, error) {
+
+	timeRange := bson.D{}
+	if from != nil {
+		timeRange = append(timeRange, bson.E{"$gte", *from})
+	}
+	if to != nil {
+		timeRange = append(timeRange, bson.E{"$lt", *to})
+	}
+	filter := bson.D{{"fleetId", fleetId}}
+	if len(timeRange) > 0 {
+		filter = append(filter, bson.E{"timestamp", timeRange})
+	}
+
+	// the entries of a single change share their timestamp, so they are ordered by their (increasing) IDs as well
+	opts := options.Find().
+		SetSort(bson.D{{"timestamp", 1}, {"_id", 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.database.Collection(m.auditCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []auditEntry
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	if len(documents) == 0 {
+		// the history of deleted fleets is kept, so only fleets which never existed are unknown
+		count, err := m.database.Collection(m.auditCollection).
+			CountDocuments(ctx, bson.D{{"fleetId", fleetId}}, options.Count().SetLimit(1))
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			if err := m.checkFleetExists(ctx, fleetId); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// always return a non-nil slice so that an empty result is serialized as an empty array
+	entries := make([]model.AuditEntry, len(documents))
+	for index, document := range documents {
+		entries[index] = document.toModel()
+	}
+	return entries, nil
+}
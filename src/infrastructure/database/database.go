@@ -4,6 +4,7 @@ import (
 	"PFleetManagement/logic/fleetErrors"
 	"PFleetManagement/logic/model"
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -11,16 +12,80 @@ import (
 )
 
 const fleetCollectionBaseName = "fleets"
+const assignmentCollectionBaseName = "assignments"
+const auditCollectionBaseName = "audit"
+const membershipCollectionBaseName = "memberships"
 
 type connection struct {
-	database   *mongo.Database
-	client     *mongo.Client
-	collection string
+	database             *mongo.Database
+	client               *mongo.Client
+	collection           string
+	assignmentCollection string
+	auditCollection      string
+	membershipCollection string
 }
 
 type fleet struct {
-	FleetId model.FleetID `bson:"_id"`
-	Vins    []model.Vin   `bson:"vins"`
+	FleetId     model.FleetID     `bson:"_id"`
+	Name        string            `bson:"name,omitempty"`
+	Description string            `bson:"description,omitempty"`
+	Owner       string            `bson:"owner,omitempty"`
+	Labels      map[string]string `bson:"labels,omitempty"`
+	CreatedAt   time.Time         `bson:"createdAt"`
+	UpdatedAt   time.Time         `bson:"updatedAt"`
+}
+
+// assignment connects a car (by its VIN) to a fleet for the interval from AssignedAt to UnassignedAt. Assignments
+// are not deleted when a car leaves a fleet, but their interval is closed, so that the composition of a fleet at a
+// past point in time can be reconstructed. As the VIN is unique among all active assignments (with UnassignedAt
+// stored as null), a car cannot be assigned to multiple fleets at the same time.
+type assignment struct {
+	Vin          model.Vin     `bson:"vin"`
+	FleetId      model.FleetID `bson:"fleetId"`
+	AssignedAt   time.Time     `bson:"assignedAt"`
+	UnassignedAt *time.Time    `bson:"unassignedAt"`
+}
+
+// activeVinIndex is the name of the index enforcing that every car is assigned to at most one fleet at the same time
+const activeVinIndex = "activeVin"
+
+// isActive selects the active assignments. It matches the filter of activeVinIndex exactly (instead of also matching
+// a missing field), so that queries for active assignments by VIN can use that index.
+var isActive = bson.E{"unassignedAt", bson.D{{"$type", "null"}}}
+
+// activeAt selects the assignments which were active at the given point in time
+func activeAt(asOf time.Time) bson.D {
+	return bson.D{
+		{"assignedAt", bson.D{{"$lte", asOf}}},
+		{"$or", bson.A{
+			bson.D{isActive},
+			bson.D{{"unassignedAt", bson.D{{"$gt", asOf}}}},
+		}},
+	}
+}
+
+// closeAssignments is the update closing the interval of the matched assignments at the given point in time
+func closeAssignments(unassignedAt time.Time) bson.D {
+	return bson.D{{"$set", bson.D{{"unassignedAt", unassignedAt}}}}
+}
+
+// toModel converts the stored fleet document to the fleet exposed by the model (i.e. without assignments)
+func (f *fleet) toModel() *model.Fleet {
+	return &model.Fleet{
+		CreatedAt:   f.CreatedAt,
+		Description: f.Description,
+		FleetID:     f.FleetId,
+		Labels:      f.Labels,
+		Name:        f.Name,
+		Owner:       f.Owner,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+// now returns the current time as it is stored by MongoDB (UTC with millisecond precision) so that
+// timestamps returned from write operations are equal to those read from the database later on
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
 }
 
 func OpenDatabase(config Config) (FleetDB, error) {
@@ -28,7 +93,8 @@ func OpenDatabase(config Config) (FleetDB, error) {
 	return &m, m.setUpDatabase(config) // return the error (if) encountered in setup
 }
 
-func (m *connection) setUpDatabase(config Config) error {
+// setUpDatabase connects to the configured database, applying the given client options on top of the configuration
+func (m *connection) setUpDatabase(config Config, clientOptions ...*options.ClientOptions) error {
 	// create the client options and construct the MongoDB connection URI from environment variables
 	opts := options.Client()
 	opts.ApplyURI(config.GetMongoDbConnectionString())
@@ -40,7 +106,7 @@ func (m *connection) setUpDatabase(config Config) error {
 	defer cancel()
 
 	// connect to the MongoDB server
-	m.client, err = mongo.Connect(ctx, opts)
+	m.client, err = mongo.Connect(ctx, append([]*options.ClientOptions{opts}, clientOptions...)...)
 	if err != nil {
 		return err
 	}
@@ -50,58 +116,161 @@ func (m *connection) setUpDatabase(config Config) error {
 		return err
 	}
 
+	// every write uses a transaction, so writes would fail later on without a replica set
+	if err = m.checkTransactionsSupported(ctx); err != nil {
+		return err
+	}
+
 	// store an additional pointer to the database of which the name is given by the environment
 	m.database = m.client.Database(config.GetMongoDbDatabase(), options.Database())
 
-	// save the collection name
+	// save the collection names
 	m.collection = config.GetAppCollectionPrefix() + fleetCollectionBaseName
+	m.assignmentCollection = config.GetAppCollectionPrefix() + assignmentCollectionBaseName
+	m.auditCollection = config.GetAppCollectionPrefix() + auditCollectionBaseName
+	m.membershipCollection = config.GetAppCollectionPrefix() + membershipCollectionBaseName
+
+	// the preparation of the collections may take longer than connecting, so it is not limited by the timeout
+	setupCtx := context.Background()
+
+	// keep assignments stored by previous versions as active intervals (before the indexes depending on them exist)
+	if err = m.migrateAssignmentIntervals(setupCtx); err != nil {
+		return err
+	}
+
+	// enforce that every car is assigned to at most one fleet at the same time (the closed intervals of a car are
+	// kept, so only the active assignments are unique) and support the queries for the cars of a fleet
+	_, err = m.database.Collection(m.assignmentCollection).Indexes().CreateMany(setupCtx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{"vin", 1}},
+			Options: options.Index().
+				SetName(activeVinIndex).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{isActive}),
+		},
+		{Keys: bson.D{{"fleetId", 1}, {"assignedAt", 1}, {"vin", 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// support the queries for the history of a fleet
+	_, err = m.database.Collection(m.auditCollection).Indexes().CreateOne(setupCtx, mongo.IndexModel{
+		Keys: bson.D{{"fleetId", 1}, {"timestamp", 1}, {"_id", 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = m.setUpMemberships(setupCtx); err != nil {
+		return err
+	}
+
+	// move assignments stored by previous versions into the assignment collection
+	return m.migrateFleetVins(setupCtx)
+}
+
+// checkTransactionsSupported fails if the server is neither a member of a replica set nor a router of a sharded cluster,
+// as MongoDB only supports transactions on these deployments
+func (m *connection) checkTransactionsSupported(ctx context.Context) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := m.client.Database("admin").RunCommand(ctx, bson.D{{"hello", 1}}).Decode(&hello)
+	if err != nil {
+		return err
+	}
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return errors.New("MongoDB is not running as a replica set, which is required for transactions")
+	}
 	return nil
 }
 
+func (m *connection) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, nil)
+}
+
 func (m *connection) CleanUpDatabase() error {
 	return m.client.Disconnect(context.Background())
 }
 
-func (m *connection) AddFleet(ctx context.Context, fleetId model.FleetID) error {
-	// create a new object with the given fleet ID and an empty car/VIN list
-	_, err := m.database.Collection(m.collection).
-		InsertOne(ctx, fleet{FleetId: fleetId, Vins: []model.Vin{}})
+func (m *connection) AddFleet(ctx context.Context, fleetCreation model.FleetCreation) (*model.Fleet, error) {
+	creationTime := now()
+
+	// create a new object with the given fleet ID and metadata (cars are assigned in a separate collection)
+	document := fleet{
+		FleetId:     fleetCreation.FleetID,
+		Name:        fleetCreation.Name,
+		Description: fleetCreation.Description,
+		Owner:       fleetCreation.Owner,
+		Labels:      fleetCreation.Labels,
+		CreatedAt:   creationTime,
+		UpdatedAt:   creationTime,
+	}
+	err := m.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.database.Collection(m.collection).InsertOne(ctx, document); err != nil {
+			return err
+		}
+		return m.audit(ctx, newAuditEntry(ctx, creationTime, model.AuditFleetCreated, document.FleetId))
+	})
 
 	// MongoDB detects duplicate _id (in BSON, field FleetId in struct)
 	if mongo.IsDuplicateKeyError(err) {
-		return fleetErrors.ErrFleetAlreadyExists
+		return nil, fleetErrors.ErrFleetAlreadyExists
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return err
+	return document.toModel(), nil
 }
 
-func (m *connection) AddCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
-	// update only the fleet given by ID
-	filter := bson.D{{"_id", fleetId}}
-	// the $addToSet operator guarantees that the VIN will not occur multiple times in the resulting array/set
-	update := bson.D{{"$addToSet", bson.D{{"vins", vin}}}}
-	// perform the addition as atomic MongoDB document update (updateOne does not fail, if none found, see below)
-	result, err := m.database.Collection(m.collection).UpdateOne(ctx, filter, update)
+func (m *connection) UpdateFleet(ctx context.Context, fleetId model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
 
-	if err != nil {
-		// return database error
-		return err
+	// only set those fields which are given in the update, but always record the time of the change
+	updateTime := now()
+	fields := bson.D{{"updatedAt", updateTime}}
+	if update.Name != nil {
+		fields = append(fields, bson.E{"name", *update.Name})
 	}
-	if result.MatchedCount == 0 {
-		// this case occurs if the filter (by fleet ID) did not match any document -> no fleet with that ID exists
-		return fleetErrors.ErrFleetNotFound
+	if update.Description != nil {
+		fields = append(fields, bson.E{"description", *update.Description})
+	}
+	if update.Owner != nil {
+		fields = append(fields, bson.E{"owner", *update.Owner})
 	}
-	if result.ModifiedCount == 0 {
-		//  -> the VIN was already in the set
-		// if a document matched but none was modified, the $addToSet did not perform a change
-		return fleetErrors.ErrCarAlreadyInFleet
+	if update.Labels != nil {
+		fields = append(fields, bson.E{"labels", *update.Labels})
 	}
 
-	// no error nor invalid post conditions -> success
-	return nil
+	// perform the atomic update and read the document as it is after the update
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var fleet fleet
+	err := m.inTransaction(ctx, func(ctx context.Context) error {
+		err := m.database.Collection(m.collection).
+			FindOneAndUpdate(ctx, bson.D{{"_id", fleetId}}, bson.D{{"$set", fields}}, opts).
+			Decode(&fleet)
+		if err != nil {
+			return err
+		}
+		return m.audit(ctx, newAuditEntry(ctx, updateTime, model.AuditFleetUpdated, fleetId))
+	})
+
+	if err == mongo.ErrNoDocuments {
+		// this error is returned if no fleet matched the ID filter
+		return nil, fleetErrors.ErrFleetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fleet.toModel(), nil
 }
 
-func (m *connection) GetCarsForFleet(ctx context.Context, fleetId model.FleetID) ([]model.Vin, error) {
+func (m *connection) GetFleet(ctx context.Context, fleetId model.FleetID) (*model.Fleet, error) {
 	var fleet fleet
 
 	// create a query with filter by _id (aka FleetId) and decode the document to the struct, fails if fleet not found
@@ -113,61 +282,527 @@ func (m *connection) GetCarsForFleet(ctx context.Context, fleetId model.FleetID)
 		// this error is returned if no fleet matched the ID filter
 		return nil, fleetErrors.ErrFleetNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	// the fleet.Vins might be invalid data if Decode returned an error but errors should be checked first
-	return fleet.Vins, err
+	return fleet.toModel(), nil
 }
 
-func (m *connection) IsCarInFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) (bool, error) {
-	// just delegates to read list of VINs of all cars assigned to fleet
-	vins, err := m.GetCarsForFleet(ctx, fleetId)
+func (m *connection) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	// an empty filter matches all fleet documents, sorted by ID for a stable order
+	cursor, err := m.database.Collection(m.collection).
+		Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
 
-	// handle downstream errors
+	var documents []fleet
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	// always return a non-nil slice so that an empty result is serialized as an empty array
+	fleets := make([]model.Fleet, len(documents))
+	for index, document := range documents {
+		fleets[index] = *document.toModel()
+	}
+
+	return fleets, nil
+}
+
+func (m *connection) GetFleetSizes(ctx context.Context) (map[model.FleetID]int, error) {
+	cursor, err := m.database.Collection(m.collection).
+		Find(ctx, bson.D{}, options.Find().SetProjection(bson.D{{"_id", 1}}))
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	var fleets []fleet
+	if err = cursor.All(ctx, &fleets); err != nil {
+		return nil, err
+	}
+
+	// fleets without any car do not occur in the aggregation below
+	sizes := make(map[model.FleetID]int, len(fleets))
+	for _, document := range fleets {
+		sizes[document.FleetId] = 0
+	}
+
+	// the active assignments are counted by the database, so that no VINs are transferred
+	cursor, err = m.database.Collection(m.assignmentCollection).Aggregate(ctx, mongo.Pipeline{
+		{{"$match", bson.D{isActive}}},
+		{{"$group", bson.D{{"_id", "$fleetId"}, {"size", bson.D{{"$sum", 1}}}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		FleetId model.FleetID `bson:"_id"`
+		Size    int           `bson:"size"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
 	}
 
-	// iterate through VINs and return true (car is in fleet), as soon as the VIN to check for is found
-	for _, foundVin := range vins {
-		if foundVin == vin {
-			return true, nil
+	for _, group := range groups {
+		// assignments of a fleet which has just been deleted are not counted
+		if _, ok := sizes[group.FleetId]; ok {
+			sizes[group.FleetId] = group.Size
 		}
 	}
+	return sizes, nil
+}
+
+func (m *connection) DeleteFleet(ctx context.Context, fleetId model.FleetID) error {
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := m.database.Collection(m.collection).DeleteOne(ctx, bson.D{{"_id", fleetId}})
+
+		if err != nil {
+			// return database error
+			return err
+		}
+		if result.DeletedCount == 0 {
+			// this case occurs if the filter (by fleet ID) did not match any document -> no fleet with that ID exists
+			return fleetErrors.ErrFleetNotFound
+		}
+
+		// the cars of the deleted fleet are not assigned to any fleet anymore, which is recorded for every car
+		cursor, err := m.database.Collection(m.assignmentCollection).
+			Find(ctx, bson.D{{"fleetId", fleetId}, isActive})
+		if err != nil {
+			return err
+		}
+		vins, err := vinsOf(ctx, cursor)
+		if err != nil {
+			return err
+		}
 
-	// no return from inside the loop -> no VIN matched -> car not in fleet
-	return false, nil
+		// the past assignments are deleted as well, as a fleet with the same ID must not inherit them (the changes
+		// are still recorded in the history)
+		_, err = m.database.Collection(m.assignmentCollection).DeleteMany(ctx, bson.D{{"fleetId", fleetId}})
+		if err != nil {
+			return err
+		}
+
+		// the roles in the fleet are revoked, as they must not be inherited by a fleet with the same ID either
+		_, err = m.database.Collection(m.membershipCollection).DeleteMany(ctx, bson.D{{"fleetId", fleetId}})
+		if err != nil {
+			return err
+		}
+
+		deletionTime := now()
+		entries := make([]auditEntry, 0, len(vins)+1)
+		for _, vin := range vins {
+			entries = append(entries, newCarAuditEntry(ctx, deletionTime, model.AuditCarRemoved, fleetId, vin))
+		}
+		entries = append(entries, newAuditEntry(ctx, deletionTime, model.AuditFleetDeleted, fleetId))
+		return m.audit(ctx, entries...)
+	})
 }
 
-func (m *connection) RemoveCarFromFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
-	// update only the fleet given by ID
-	filter := bson.D{{"_id", fleetId}}
-	// the $pullAll operator deletes (an array of) values from an array atomically
-	update := bson.D{{
-		"$pullAll", bson.D{{
-			"vins", bson.A{vin},
-		}},
-	}}
-	// perform the atomic update
-	result, err := m.database.Collection(m.collection).UpdateOne(ctx, filter, update)
+// checkFleetExists returns fleetErrors.ErrFleetNotFound if there is no fleet with the given ID
+func (m *connection) checkFleetExists(ctx context.Context, fleetId model.FleetID) error {
+	count, err := m.database.Collection(m.collection).
+		CountDocuments(ctx, bson.D{{"_id", fleetId}}, options.Count().SetLimit(1))
 
 	if err != nil {
-		// return database error
 		return err
 	}
-	if result.MatchedCount == 0 {
-		// this case occurs if the filter (by fleet ID) did not match any document -> no fleet with that ID exists
+	if count == 0 {
 		return fleetErrors.ErrFleetNotFound
 	}
-	if result.ModifiedCount == 0 {
-		// if a document matched but none was modified, the $pullAll did not perform a change
-		//  -> the VIN was not contained in the array
-		return fleetErrors.ErrCarNotInFleet
+	return nil
+}
+
+func (m *connection) AddCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	for attempt := 0; ; attempt++ {
+		err := m.assignCar(ctx, fleetId, vin)
+
+		// the unique index on the VIN detects that the car is already assigned (to this or another fleet)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		var existing assignment
+		err = m.database.Collection(m.assignmentCollection).
+			FindOne(ctx, bson.D{{"vin", vin}, isActive}).
+			Decode(&existing)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// the conflicting assignment was closed in the meantime, so the car can be assigned after all
+			if attempt == 0 {
+				continue
+			}
+			// the car keeps being reassigned concurrently, which is reported like any other competing assignment
+			return fleetErrors.ErrCarAssignedToOtherFleet
+		}
+		if err != nil {
+			return err
+		}
+		if existing.FleetId == fleetId {
+			return fleetErrors.ErrCarAlreadyInFleet
+		}
+		return fleetErrors.ErrCarAssignedToOtherFleet
 	}
+}
 
-	// no error nor invalid post conditions -> success
-	return nil
+// assignCar creates the active assignment of the car to the fleet
+func (m *connection) assignCar(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+
+		document := assignment{
+			Vin:        vin,
+			FleetId:    fleetId,
+			AssignedAt: now(),
+		}
+		if _, err := m.database.Collection(m.assignmentCollection).InsertOne(ctx, document); err != nil {
+			return err
+		}
+		return m.audit(ctx, newCarAuditEntry(ctx, document.AssignedAt, model.AuditCarAdded, fleetId, vin))
+	})
+}
+
+func (m *connection) AddCarsToFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) (map[model.Vin]error, error) {
+
+	failures, err := m.assignCars(ctx, fleetId, vins)
+
+	// the unique index on the VIN detects cars which were assigned after the existing assignments were checked
+	if mongo.IsDuplicateKeyError(err) {
+		// the cars are checked once more, which reports those cars as already assigned
+		failures, err = m.assignCars(ctx, fleetId, vins)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		// the cars keep being assigned concurrently, which is reported like any other competing assignment
+		return nil, fleetErrors.ErrCarAssignedToOtherFleet
+	}
+	if err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+// assignCars creates the active assignments of those of the cars to the fleet which are not assigned yet and returns
+// the failures for the others
+func (m *connection) assignCars(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) (map[model.Vin]error, error) {
+
+	var failures map[model.Vin]error
+	err := m.inTransaction(ctx, func(ctx context.Context) error {
+		// (the transaction may be repeated, so every attempt starts from scratch)
+		failures = make(map[model.Vin]error)
+
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+		if len(vins) == 0 {
+			return nil
+		}
+
+		// the cars which are already assigned (to this or another fleet) are skipped
+		cursor, err := m.database.Collection(m.assignmentCollection).
+			Find(ctx, bson.D{{"vin", bson.D{{"$in", vins}}}, isActive})
+		if err != nil {
+			return err
+		}
+		var existing []assignment
+		if err = cursor.All(ctx, &existing); err != nil {
+			return err
+		}
+		for _, existingAssignment := range existing {
+			if existingAssignment.FleetId == fleetId {
+				failures[existingAssignment.Vin] = fleetErrors.ErrCarAlreadyInFleet
+			} else {
+				failures[existingAssignment.Vin] = fleetErrors.ErrCarAssignedToOtherFleet
+			}
+		}
+
+		assignedAt := now()
+		var documents []interface{}
+		var entries []auditEntry
+		for _, vin := range vins {
+			if failures[vin] != nil {
+				continue
+			}
+			documents = append(documents, assignment{
+				Vin:        vin,
+				FleetId:    fleetId,
+				AssignedAt: assignedAt,
+			})
+			entries = append(entries, newCarAuditEntry(ctx, assignedAt, model.AuditCarAdded, fleetId, vin))
+		}
+		if len(documents) == 0 {
+			return nil
+		}
+
+		// all remaining cars are assigned with a single write
+		if _, err = m.database.Collection(m.assignmentCollection).InsertMany(ctx, documents); err != nil {
+			return err
+		}
+		return m.audit(ctx, entries...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+// inTransaction runs the given function in a MongoDB transaction which is committed if the function succeeds and
+// aborted otherwise. The function may be retried on transient transaction errors, so it must not have side effects
+// outside the database. All database operations of the function must use the passed context.
+func (m *connection) inTransaction(ctx context.Context, transaction func(ctx context.Context) error) error {
+	session, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, transaction(sessionCtx)
+	})
+	return err
+}
+
+func (m *connection) MoveCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin,
+	targetFleetId model.FleetID) error {
+
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		// both fleets are checked in the transaction, so neither can vanish before the assignment is changed
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+		if err := m.checkFleetExists(ctx, targetFleetId); err != nil {
+			return err
+		}
+
+		filter := bson.D{{"vin", vin}, {"fleetId", fleetId}, isActive}
+
+		if fleetId == targetFleetId {
+			// nothing to move, the original assignment date is kept
+			count, err := m.database.Collection(m.assignmentCollection).CountDocuments(ctx, filter)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return fleetErrors.ErrCarNotInFleet
+			}
+			return fleetErrors.ErrCarAlreadyInFleet
+		}
+
+		// the assignment to the source fleet is closed before the car is assigned to the target fleet
+		moveTime := now()
+		result, err := m.database.Collection(m.assignmentCollection).
+			UpdateOne(ctx, filter, closeAssignments(moveTime))
+
+		if err != nil {
+			// return database error
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fleetErrors.ErrCarNotInFleet
+		}
+
+		_, err = m.database.Collection(m.assignmentCollection).InsertOne(ctx, assignment{
+			Vin:        vin,
+			FleetId:    targetFleetId,
+			AssignedAt: moveTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the move is recorded in the history of both fleets
+		movedOut := newCarAuditEntry(ctx, moveTime, model.AuditCarMovedOut, fleetId, vin)
+		movedOut.OtherFleetId = targetFleetId
+		movedIn := newCarAuditEntry(ctx, moveTime, model.AuditCarMovedIn, targetFleetId, vin)
+		movedIn.OtherFleetId = fleetId
+		return m.audit(ctx, movedOut, movedIn)
+	})
+}
+
+// vinsOf extracts the VINs from the assignments matched by the given cursor
+func vinsOf(ctx context.Context, cursor *mongo.Cursor) ([]model.Vin, error) {
+	var assignments []assignment
+	if err := cursor.All(ctx, &assignments); err != nil {
+		return nil, err
+	}
+
+	// always return a non-nil slice so that an empty result is serialized as an empty array
+	vins := make([]model.Vin, len(assignments))
+	for index, assignment := range assignments {
+		vins[index] = assignment.Vin
+	}
+	return vins, nil
+}
+
+// assignmentsOf selects the assignments of the given fleet which are active at the given point in time or now
+func assignmentsOf(fleetId model.FleetID, asOf *time.Time) bson.D {
+	if asOf == nil {
+		return bson.D{{"fleetId", fleetId}, isActive}
+	}
+	return append(bson.D{{"fleetId", fleetId}}, activeAt(*asOf)...)
+}
+
+func (m *connection) GetCarsForFleet(ctx context.Context, fleetId model.FleetID,
+	asOf *time.Time) ([]model.Vin, error) {
+
+	if err := m.checkFleetExists(ctx, fleetId); err != nil {
+		return nil, err
+	}
+
+	// the cars are returned in the order in which they were assigned to the fleet
+	cursor, err := m.database.Collection(m.assignmentCollection).
+		Find(ctx, assignmentsOf(fleetId, asOf), options.Find().SetSort(bson.D{{"assignedAt", 1}, {"vin", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	return vinsOf(ctx, cursor)
+}
+
+func (m *connection) GetCarsForFleetPage(ctx context.Context, fleetId model.FleetID, asOf *time.Time,
+	order VinOrder, offset, limit int) ([]model.Vin, error) {
+
+	if err := m.checkFleetExists(ctx, fleetId); err != nil {
+		return nil, err
+	}
+
+	sort := bson.D{{"assignedAt", 1}, {"vin", 1}}
+	switch order {
+	case VinOrderAscending:
+		sort = bson.D{{"vin", 1}}
+	case VinOrderDescending:
+		sort = bson.D{{"vin", -1}}
+	}
+
+	// the assignments are sorted and paged by the database so that only the page is transferred
+	opts := options.Find().SetSort(sort).SetSkip(int64(offset)).SetLimit(int64(limit))
+	cursor, err := m.database.Collection(m.assignmentCollection).Find(ctx, assignmentsOf(fleetId, asOf), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return vinsOf(ctx, cursor)
+}
+
+func (m *connection) IsCarInFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) (bool, error) {
+	count, err := m.database.Collection(m.assignmentCollection).
+		CountDocuments(ctx, bson.D{{"vin", vin}, {"fleetId", fleetId}, isActive}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// the car is not in the fleet, but the fleet might not exist at all
+	return false, m.checkFleetExists(ctx, fleetId)
+}
+
+func (m *connection) RemoveCarFromFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		// only close the assignment if the car is assigned to the given fleet
+		removalTime := now()
+		result, err := m.database.Collection(m.assignmentCollection).
+			UpdateOne(ctx, bson.D{{"vin", vin}, {"fleetId", fleetId}, isActive}, closeAssignments(removalTime))
+
+		if err != nil {
+			// return database error
+			return err
+		}
+		if result.MatchedCount == 0 {
+			// either the fleet does not exist or the car is not assigned to it
+			if err := m.checkFleetExists(ctx, fleetId); err != nil {
+				return err
+			}
+			return fleetErrors.ErrCarNotInFleet
+		}
+
+		return m.audit(ctx, newCarAuditEntry(ctx, removalTime, model.AuditCarRemoved, fleetId, vin))
+	})
+}
+
+func (m *connection) RemoveCarsFromFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) ([]model.Vin, error) {
+
+	var removed []model.Vin
+	err := m.inTransaction(ctx, func(ctx context.Context) error {
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+
+		// the assignments are read first to report which cars were actually assigned to the fleet
+		filter := bson.D{{"vin", bson.D{{"$in", vins}}}, {"fleetId", fleetId}, isActive}
+		cursor, err := m.database.Collection(m.assignmentCollection).Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if removed, err = vinsOf(ctx, cursor); err != nil {
+			return err
+		}
+
+		removalTime := now()
+		_, err = m.database.Collection(m.assignmentCollection).UpdateMany(ctx, filter, closeAssignments(removalTime))
+		if err != nil {
+			return err
+		}
+
+		entries := make([]auditEntry, len(removed))
+		for index, vin := range removed {
+			entries[index] = newCarAuditEntry(ctx, removalTime, model.AuditCarRemoved, fleetId, vin)
+		}
+		return m.audit(ctx, entries...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (m *connection) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		// the assignments are read first to record the decommissioning in the history of every affected fleet
+		filter := bson.D{{"vin", vin}, isActive}
+		cursor, err := m.database.Collection(m.assignmentCollection).Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+		var assignments []assignment
+		if err = cursor.All(ctx, &assignments); err != nil {
+			return err
+		}
+
+		decommissioningTime := now()
+		_, err = m.database.Collection(m.assignmentCollection).
+			UpdateMany(ctx, filter, closeAssignments(decommissioningTime))
+		if err != nil {
+			return err
+		}
+
+		// the history is kept per fleet, so nothing is recorded for a car which is not assigned to any fleet
+		entries := make([]auditEntry, len(assignments))
+		for index, document := range assignments {
+			entries[index] = newCarAuditEntry(ctx, decommissioningTime, model.AuditCarDecommissioned,
+				document.FleetId, vin)
+			entries[index].Reason = reason
+		}
+		return m.audit(ctx, entries...)
+	})
 }
 
 func (m *connection) DropCollection(ctx context.Context) error {
+	// the assignments, the audit entries and the memberships are deleted instead of dropping their collections to
+	// keep their indexes
+	for _, collection := range []string{m.assignmentCollection, m.auditCollection, m.membershipCollection} {
+		if _, err := m.database.Collection(collection).DeleteMany(ctx, bson.D{}); err != nil {
+			return err
+		}
+	}
 	return m.database.Collection(m.collection).Drop(ctx)
 }
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"PFleetManagement/environment"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const raceVin = "WVWAA71K08W201030"
+const otherRaceVin = "WVWAA71K08W201031"
+
+// openTestDatabase connects to the database configured like for the integration tests (the test is skipped if no
+// database is configured) and drops the collections of the test afterwards
+func openTestDatabase(t *testing.T, clientOptions ...*options.ClientOptions) *connection {
+	if os.Getenv("MONGODB_CONNECTION_STRING") == "" && os.Getenv("FM_LOCAL_SETUP") == "" {
+		t.Skip("no database is configured")
+	}
+	environment.SetupTestingEnvironment(
+		"https://carservice.kit.edu",
+		"https://rentalmanagement.kit.edu",
+	)
+
+	// generate a collection name so that concurrent executions do not interfere
+	environment.GetEnvironment().SetAppCollectionPrefix(fmt.Sprintf("test-%d-", time.Now().UnixNano()))
+
+	m := &connection{}
+	if err := m.setUpDatabase(environment.GetEnvironment(), clientOptions...); err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() {
+		for _, collection := range []string{m.collection, m.assignmentCollection, m.auditCollection,
+			m.membershipCollection} {
+			_ = m.database.Collection(collection).Drop(context.Background())
+		}
+		_ = m.CleanUpDatabase()
+	})
+	return m
+}
+
+// afterCommand returns client options which run the action once, as soon as the next command of the given name is
+// completed after armed has been set, so that tests can interleave a concurrent modification with an operation
+func afterCommand(commandName string, armed *atomic.Bool, action func()) *options.ClientOptions {
+	run := func(name string) {
+		if name == commandName && armed.CompareAndSwap(true, false) {
+			action()
+		}
+	}
+	return options.Client().SetMonitor(&event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			run(e.CommandName)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			run(e.CommandName)
+		},
+	})
+}
+
+func TestConnection_AddCarToFleet_conflictingAssignmentClosed(t *testing.T) {
+	ctx := context.Background()
+
+	// as soon as the assignment of the car is rejected, the conflicting assignment is closed, i.e. before the
+	// conflict is examined
+	var m *connection
+	var armed atomic.Bool
+	m = openTestDatabase(t, afterCommand("insert", &armed, func() {
+		_, err := m.RemoveCarsFromFleet(ctx, "other", []model.Vin{raceVin})
+		assert.Nil(t, err)
+	}))
+
+	for _, fleetId := range []model.FleetID{"fleet", "other"} {
+		_, err := m.AddFleet(ctx, model.FleetCreation{FleetID: fleetId})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, m.AddCarToFleet(ctx, "other", raceVin))
+
+	armed.Store(true)
+	err := m.AddCarToFleet(ctx, "fleet", raceVin)
+
+	assert.Nil(t, err)
+	assert.False(t, armed.Load())
+	vins, err := m.GetCarsForFleet(ctx, "fleet", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []model.Vin{raceVin}, vins)
+}
+
+func TestConnection_AddCarsToFleet_carAssignedConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	// as soon as the existing assignments are checked, the car is assigned to another fleet, i.e. before the cars
+	// are assigned
+	var m *connection
+	var armed atomic.Bool
+	m = openTestDatabase(t, afterCommand("find", &armed, func() {
+		assert.Nil(t, m.AddCarToFleet(ctx, "other", raceVin))
+	}))
+
+	for _, fleetId := range []model.FleetID{"fleet", "other"} {
+		_, err := m.AddFleet(ctx, model.FleetCreation{FleetID: fleetId})
+		assert.Nil(t, err)
+	}
+
+	armed.Store(true)
+	failures, err := m.AddCarsToFleet(ctx, "fleet", []model.Vin{raceVin, otherRaceVin})
+
+	assert.Nil(t, err)
+	assert.False(t, armed.Load())
+	assert.Equal(t, map[model.Vin]error{raceVin: fleetErrors.ErrCarAssignedToOtherFleet}, failures)
+	vins, err := m.GetCarsForFleet(ctx, "fleet", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []model.Vin{otherRaceVin}, vins)
+}
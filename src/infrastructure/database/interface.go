@@ -5,31 +5,122 @@ package database
 import (
 	"PFleetManagement/logic/model"
 	"context"
+	"time"
+)
+
+// VinOrder defines the order in which the VINs assigned to a fleet are paged
+type VinOrder int
+
+const (
+	// VinOrderAssigned keeps the order in which the cars were assigned to the fleet
+	VinOrderAssigned VinOrder = iota
+
+	// VinOrderAscending sorts the VINs lexicographically
+	VinOrderAscending
+
+	// VinOrderDescending sorts the VINs lexicographically in reverse
+	VinOrderDescending
 )
 
 // FleetDB Abstraction over database backends to manage car-fleet assignment.
+// Every car is assigned to at most one fleet at the same time. The past assignments of existing fleets are kept, so
+// that the composition of a fleet can be read as of a past point in time.
+// Every change of a fleet or its assignments is recorded in an append-only history together with the change itself,
+// attributed to the caller.Info of the context.
+// Every write is performed in a transaction, so the MongoDB implementation requires a replica set (or a sharded
+// cluster) and fails to open the database otherwise.
 // Returns errors as defined in logic/operations
 type FleetDB interface {
-	// AddFleet creates a new empty fleet. This is necessary before a car can be assigned to it.
-	AddFleet(ctx context.Context, fleetId model.FleetID) error
+	// AddFleet creates a new empty fleet with the given descriptive data and returns it including its timestamps.
+	// This is necessary before a car can be assigned to it.
+	AddFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error)
+
+	// UpdateFleet replaces the given (non-nil) descriptive data of the given fleet and returns the updated fleet
+	UpdateFleet(ctx context.Context, fleetId model.FleetID, update model.FleetUpdate) (*model.Fleet, error)
+
+	// GetFleet reads the fleet with the given ID. Fails if the fleet does not exist.
+	GetFleet(ctx context.Context, fleetId model.FleetID) (*model.Fleet, error)
+
+	// ListFleets reads all fleets known to the database
+	ListFleets(ctx context.Context) ([]model.Fleet, error)
+
+	// GetFleetSizes reads the number of cars which are currently assigned to each fleet (including empty fleets)
+	GetFleetSizes(ctx context.Context) (map[model.FleetID]int, error)
+
+	// DeleteFleet deletes the given fleet including all of its current and past car assignments and the roles of
+	// all users in it
+	DeleteFleet(ctx context.Context, fleetId model.FleetID) error
 
 	// AddCarToFleet adds a reference to the given car (by its VIN) to the given fleet.
-	// Fails on unknown fleet or duplicate entry but does not perform further checks on the VIN.
+	// Fails on unknown fleet, duplicate entry or if the car is assigned to another fleet, but does not perform
+	// further checks on the VIN.
 	AddCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error
 
+	// AddCarsToFleet adds references to the given cars (by their VINs) to the given fleet in a single write.
+	// Fails on unknown fleet. Cars which are already assigned to the given or another fleet are skipped and reported
+	// with fleetErrors.ErrCarAlreadyInFleet or fleetErrors.ErrCarAssignedToOtherFleet in the returned map. Fails with
+	// fleetErrors.ErrCarAssignedToOtherFleet if the cars are repeatedly assigned concurrently while they are checked.
+	AddCarsToFleet(ctx context.Context, fleetId model.FleetID, vins []model.Vin) (map[model.Vin]error, error)
+
+	// MoveCarToFleet changes the assignment of the given car from the given fleet to the target fleet in a transaction.
+	// Fails on unknown fleets or if the car is not assigned to the given fleet.
+	MoveCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin, targetFleetId model.FleetID) error
+
 	// RemoveCarFromFleet removes the reference to the given car (its VIN) from the given fleet if it is contained
 	RemoveCarFromFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error
 
-	// GetCarsForFleet reads the VINs of the cars which are assigned to the given fleet
-	GetCarsForFleet(ctx context.Context, fleetId model.FleetID) ([]model.Vin, error)
+	// RemoveCarsFromFleet removes the references to those of the given cars which are assigned to the given fleet
+	// in a transaction and returns their VINs. Fails on unknown fleet.
+	RemoveCarsFromFleet(ctx context.Context, fleetId model.FleetID, vins []model.Vin) ([]model.Vin, error)
+
+	// DecommissionCar removes the reference to the given car (its VIN) from every fleet it is assigned to and
+	// records its decommissioning with the given reason in the history of these fleets. If the car is not assigned
+	// to any fleet, nothing is recorded, as there is no history it could be read from.
+	DecommissionCar(ctx context.Context, vin model.Vin, reason string) error
+
+	// GetCarsForFleet reads the VINs of the cars which are assigned to the given fleet, or which were assigned to it
+	// at the given point in time if asOf is not nil
+	GetCarsForFleet(ctx context.Context, fleetId model.FleetID, asOf *time.Time) ([]model.Vin, error)
+
+	// GetCarsForFleetPage reads at most limit VINs of the cars which are assigned to the given fleet (at the given point
+	// in time if asOf is not nil), skipping the first offset VINs in the given order. Only the requested page is
+	// transferred from the database.
+	GetCarsForFleetPage(ctx context.Context, fleetId model.FleetID, asOf *time.Time, order VinOrder,
+		offset, limit int) ([]model.Vin, error)
 
 	// IsCarInFleet checks whether the given car (identified by its VIN) is assigned to the given fleet
 	IsCarInFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) (bool, error)
 
+	// GetFleetHistory reads at most limit entries of the history of the given fleet, skipping the first offset
+	// entries, in chronological order. Only entries recorded at or after from (if given) and before to (if given) are
+	// considered. The history of deleted fleets is kept, so it only fails for fleets which never existed.
+	GetFleetHistory(ctx context.Context, fleetId model.FleetID, from, to *time.Time, offset, limit int) ([]model.AuditEntry, error)
+
+	// SetRole grants the given role in the given fleet to the given user, replacing the previous role of the user in
+	// the fleet (if any). Fails on unknown fleet.
+	SetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject, role model.Role) error
+
+	// DeleteRole revokes the role of the given user in the given fleet. Fails on unknown fleet or if the user has no
+	// role in the fleet.
+	DeleteRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) error
+
+	// GetRole reads the role of the given user in the given fleet. Returns nil if the user has no role in the fleet
+	// (or the fleet does not exist).
+	GetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) (*model.Role, error)
+
+	// GetRoles reads the roles of the given user in all fleets
+	GetRoles(ctx context.Context, subject model.Subject) (map[model.FleetID]model.Role, error)
+
+	// GetMembers reads the roles of all users in the given fleet, sorted by the users. Fails on unknown fleet.
+	GetMembers(ctx context.Context, fleetId model.FleetID) ([]model.Membership, error)
+
+	// Ping checks whether the database is reachable
+	Ping(ctx context.Context) error
+
 	// CleanUpDatabase closes the connection to the database.
 	CleanUpDatabase() error
 
-	// DropCollection drops the contents of the database collection.
+	// DropCollection drops the contents of the database collections.
 	// This is a destructive operation and should only be used for testing.
 	DropCollection(ctx context.Context) error
 }
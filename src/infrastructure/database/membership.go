@@ -0,0 +1,128 @@
+package database
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// membership grants a role in a fleet to a user. Every user has at most one role per fleet.
+type membership struct {
+	FleetId   model.FleetID `bson:"fleetId"`
+	Subject   model.Subject `bson:"subject"`
+	Role      model.Role    `bson:"role"`
+	GrantedAt time.Time     `bson:"grantedAt"`
+}
+
+func (m *membership) toModel() model.Membership {
+	return model.Membership{
+		Role:    m.Role,
+		Subject: m.Subject,
+	}
+}
+
+// setUpMemberships creates the indexes of the membership collection
+func (m *connection) setUpMemberships(ctx context.Context) error {
+	// enforce a single role per user and fleet and support the queries for the fleets of a user
+	_, err := m.database.Collection(m.membershipCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"fleetId", 1}, {"subject", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"subject", 1}}},
+	})
+	return err
+}
+
+func (m *connection) SetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject,
+	role model.Role) error {
+
+	return m.inTransaction(ctx, func(ctx context.Context) error {
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+
+		// a previously granted role is replaced
+		filter := bson.D{{"fleetId", fleetId}, {"subject", subject}}
+		update := bson.D{{"$set", bson.D{{"role", role}, {"grantedAt", now()}}}}
+		_, err := m.database.Collection(m.membershipCollection).
+			UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		return err
+	})
+}
+
+func (m *connection) DeleteRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) error {
+	result, err := m.database.Collection(m.membershipCollection).
+		DeleteOne(ctx, bson.D{{"fleetId", fleetId}, {"subject", subject}})
+
+	if err != nil {
+		// return database error
+		return err
+	}
+	if result.DeletedCount == 0 {
+		// either the fleet does not exist or the user has no role in it
+		if err := m.checkFleetExists(ctx, fleetId); err != nil {
+			return err
+		}
+		return fleetErrors.ErrMembershipNotFound
+	}
+	return nil
+}
+
+func (m *connection) GetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) (*model.Role, error) {
+	var document membership
+	err := m.database.Collection(m.membershipCollection).
+		FindOne(ctx, bson.D{{"fleetId", fleetId}, {"subject", subject}}).
+		Decode(&document)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &document.Role, nil
+}
+
+func (m *connection) GetRoles(ctx context.Context, subject model.Subject) (map[model.FleetID]model.Role, error) {
+	cursor, err := m.database.Collection(m.membershipCollection).Find(ctx, bson.D{{"subject", subject}})
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []membership
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[model.FleetID]model.Role, len(documents))
+	for _, document := range documents {
+		roles[document.FleetId] = document.Role
+	}
+	return roles, nil
+}
+
+func (m *connection) GetMembers(ctx context.Context, fleetId model.FleetID) ([]model.Membership, error) {
+	if err := m.checkFleetExists(ctx, fleetId); err != nil {
+		return nil, err
+	}
+
+	cursor, err := m.database.Collection(m.membershipCollection).
+		Find(ctx, bson.D{{"fleetId", fleetId}}, options.Find().SetSort(bson.D{{"subject", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []membership
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	// always return a non-nil slice so that an empty result is serialized as an empty array
+	members := make([]model.Membership, len(documents))
+	for index, document := range documents {
+		members[index] = document.toModel()
+	}
+	return members, nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"PFleetManagement/logic/model"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
+)
+
+// legacyFleet is a fleet document as stored by previous versions, which kept the assigned VINs inside the fleet
+type legacyFleet struct {
+	FleetId model.FleetID `bson:"_id"`
+	Vins    []model.Vin   `bson:"vins"`
+}
+
+// migrateFleetVins moves the VINs stored inside fleet documents by previous versions to the assignment collection
+// and removes them from the fleet documents. If a VIN was assigned to multiple fleets, only its first assignment
+// (in the order of the fleet IDs) is kept. The migration is idempotent, so it can safely be repeated if interrupted.
+func (m *connection) migrateFleetVins(ctx context.Context) error {
+	fleets := m.database.Collection(m.collection)
+	cursor, err := fleets.Find(ctx, bson.D{{"vins", bson.D{{"$exists", true}}}},
+		options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return err
+	}
+
+	var documents []legacyFleet
+	if err = cursor.All(ctx, &documents); err != nil {
+		return err
+	}
+
+	for _, document := range documents {
+		migrationTime := now()
+		for index, vin := range document.Vins {
+			// the VINs were stored in the order of their assignment, which is kept by distinct timestamps
+			_, err := m.database.Collection(m.assignmentCollection).InsertOne(ctx, assignment{
+				Vin:        vin,
+				FleetId:    document.FleetId,
+				AssignedAt: migrationTime.Add(time.Duration(index) * time.Millisecond),
+			})
+			if mongo.IsDuplicateKeyError(err) {
+				// either migrated before the migration was interrupted or assigned to another fleet, too
+				log.Printf("migration: car %s of fleet %s is already assigned, skipping it", vin, document.FleetId)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// only remove the VINs once all of them are stored as assignments
+		_, err = fleets.UpdateOne(ctx, bson.D{{"_id", document.FleetId}}, bson.D{{"$unset", bson.D{{"vins", ""}}}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// legacyVinIndex is the name of the index which enforced unique VINs among all assignments in previous versions,
+// which did not keep the assignments of cars which left their fleet
+const legacyVinIndex = "vin_1"
+
+// migrateAssignmentIntervals converts the assignments stored by previous versions to active intervals (with an
+// explicitly unset end) and drops the index of previous versions which prevents keeping closed intervals.
+// The migration is idempotent, so it can safely be repeated if interrupted.
+func (m *connection) migrateAssignmentIntervals(ctx context.Context) error {
+	assignments := m.database.Collection(m.assignmentCollection)
+
+	specifications, err := assignments.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+	for _, specification := range specifications {
+		if specification.Name == legacyVinIndex {
+			if _, err = assignments.Indexes().DropOne(ctx, legacyVinIndex); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := assignments.UpdateMany(ctx, bson.D{{"unassignedAt", bson.D{{"$exists", false}}}},
+		bson.D{{"$set", bson.D{{"unassignedAt", nil}}}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		log.Printf("migration: kept %d assignments as active intervals", result.ModifiedCount)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package dcar
+
+import (
+	"container/list"
+	"context"
+	carTypes "github.com/ccsapp/cargotypes"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheInvalidator is implemented by clients which cache the data of cars and allows to explicitly drop the
+// cached data of a car, e.g. as soon as it is no longer assigned to a fleet.
+type CacheInvalidator interface {
+	Invalidate(vin carTypes.Vin)
+}
+
+// CacheConfig configures the CachingClient
+type CacheConfig struct {
+	// StaticTTL is the duration for which the static data of a car (everything except its dynamic data) is cached
+	StaticTTL time.Duration
+
+	// DynamicTTL is the duration for which the dynamic data of a car is cached
+	DynamicTTL time.Duration
+
+	// MaxEntries is the maximum number of cars which are cached. If exceeded, the least recently used car is evicted.
+	MaxEntries int
+}
+
+// CacheStats are the counters of a CachingClient
+type CacheStats struct {
+	// Hits is the number of car requests which were answered from the cache
+	Hits uint64
+
+	// Misses is the number of car requests which were forwarded to the Car service
+	Misses uint64
+
+	// Evictions is the number of cars which were dropped from the cache to satisfy the size limit
+	Evictions uint64
+
+	// Entries is the number of cars which are currently cached
+	Entries int
+}
+
+type staticDataSufficientKey struct{}
+
+// WithStaticDataSufficient marks a context to signal that the caller only reads the static data of the requested
+// car. A CachingClient may then return a cached car with outdated dynamic data as long as its static data is valid.
+// Other clients ignore the mark.
+func WithStaticDataSufficient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staticDataSufficientKey{}, true)
+}
+
+func isStaticDataSufficient(ctx context.Context) bool {
+	sufficient, _ := ctx.Value(staticDataSufficientKey{}).(bool)
+	return sufficient
+}
+
+type cacheEntry struct {
+	vin           carTypes.Vin
+	car           carTypes.Car
+	header        http.Header
+	staticExpiry  time.Time
+	dynamicExpiry time.Time
+}
+
+// CachingClient is a read-through cache decorating a ClientWithResponsesInterface. Successful responses of
+// GetCarWithResponse are cached with separate lifetimes for the static and the dynamic data of the car. All other
+// requests are forwarded, but deleting a car also drops it from the cache.
+type CachingClient struct {
+	ClientWithResponsesInterface
+
+	config CacheConfig
+	now    func() time.Time
+
+	mutex   sync.Mutex
+	entries map[carTypes.Vin]*list.Element
+	// recency orders the entries from the most to the least recently used one
+	recency *list.List
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// NewCachingClient wraps the given client with a read-through cache configured by the given CacheConfig
+func NewCachingClient(client ClientWithResponsesInterface, config CacheConfig) *CachingClient {
+	return &CachingClient{
+		ClientWithResponsesInterface: client,
+		config:                       config,
+		now:                          time.Now,
+		entries:                      make(map[carTypes.Vin]*list.Element),
+		recency:                      list.New(),
+	}
+}
+
+func (c *CachingClient) GetCarWithResponse(ctx context.Context, vin carTypes.VinParam,
+	reqEditors ...RequestEditorFn) (*GetCarResponse, error) {
+
+	if response := c.lookup(vin, isStaticDataSufficient(ctx)); response != nil {
+		c.hits.Add(1)
+		return response, nil
+	}
+	c.misses.Add(1)
+
+	response, err := c.ClientWithResponsesInterface.GetCarWithResponse(ctx, vin, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+
+	// only existing cars are cached, other responses (e.g. 404) are always forwarded
+	if response.JSON200 != nil && response.HTTPResponse != nil {
+		c.store(vin, response)
+	} else if response.StatusCode() == http.StatusNotFound {
+		c.Invalidate(vin)
+	}
+
+	return response, nil
+}
+
+func (c *CachingClient) DeleteCarWithResponse(ctx context.Context, vin carTypes.VinParam,
+	reqEditors ...RequestEditorFn) (*DeleteCarResponse, error) {
+
+	// drop the car regardless of the outcome as its state is unknown afterwards
+	defer c.Invalidate(vin)
+	return c.ClientWithResponsesInterface.DeleteCarWithResponse(ctx, vin, reqEditors...)
+}
+
+func (c *CachingClient) AddVehicleWithResponse(ctx context.Context, body carTypes.AddCarJSONRequestBody,
+	reqEditors ...RequestEditorFn) (*AddVehicleResponse, error) {
+
+	defer c.Invalidate(body.Vin)
+	return c.ClientWithResponsesInterface.AddVehicleWithResponse(ctx, body, reqEditors...)
+}
+
+func (c *CachingClient) AddVehicleWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader,
+	reqEditors ...RequestEditorFn) (*AddVehicleResponse, error) {
+
+	// the VIN of the added car is unknown without parsing the body, but a successful response contains it
+	response, err := c.ClientWithResponsesInterface.AddVehicleWithBodyWithResponse(ctx, contentType, body,
+		reqEditors...)
+	if err == nil && response.JSON201 != nil {
+		c.Invalidate(*response.JSON201)
+	}
+	return response, err
+}
+
+// Invalidate drops the cached data of the car with the given VIN (if any)
+func (c *CachingClient) Invalidate(vin carTypes.Vin) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[vin]; ok {
+		c.recency.Remove(element)
+		delete(c.entries, vin)
+	}
+}
+
+// Stats returns the current counters of the cache
+func (c *CachingClient) Stats() CacheStats {
+	c.mutex.Lock()
+	entries := len(c.entries)
+	c.mutex.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// lookup creates a response from the cached car with the given VIN. If there is no such car or its (relevant) data
+// is outdated, nil is returned.
+func (c *CachingClient) lookup(vin carTypes.Vin, staticDataSufficient bool) *GetCarResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[vin]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*cacheEntry)
+	now := c.now()
+	if !now.Before(entry.staticExpiry) {
+		// the whole car is outdated -> it is not kept any longer
+		c.recency.Remove(element)
+		delete(c.entries, vin)
+		return nil
+	}
+	if !staticDataSufficient && !now.Before(entry.dynamicExpiry) {
+		// the entry is kept for callers only requiring the static data until it is refreshed
+		return nil
+	}
+
+	c.recency.MoveToFront(element)
+
+	// every caller gets its own copy of the car so that the cached data cannot be modified. Copying the struct is
+	// sufficient because a car only consists of values: it contains no pointers, slices or maps which the copy would
+	// share with the cached car (guarded by TestCachingClient_carContainsNoReferences).
+	car := entry.car
+	return &GetCarResponse{
+		HTTPResponse: &http.Response{
+			Status:     http.StatusText(http.StatusOK),
+			StatusCode: http.StatusOK,
+			Header:     entry.header.Clone(),
+		},
+		JSON200: &car,
+	}
+}
+
+// store caches the car of the given successful response, evicting the least recently used car if the cache is full
+func (c *CachingClient) store(vin carTypes.Vin, response *GetCarResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	entry := &cacheEntry{
+		vin:           vin,
+		car:           *response.JSON200,
+		header:        response.HTTPResponse.Header.Clone(),
+		staticExpiry:  now.Add(c.config.StaticTTL),
+		dynamicExpiry: now.Add(c.config.DynamicTTL),
+	}
+
+	if element, ok := c.entries[vin]; ok {
+		element.Value = entry
+		c.recency.MoveToFront(element)
+		return
+	}
+
+	c.entries[vin] = c.recency.PushFront(entry)
+	for c.config.MaxEntries > 0 && len(c.entries) > c.config.MaxEntries {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).vin)
+		c.evictions.Add(1)
+	}
+}
@@ -0,0 +1,231 @@
+package dcar
+
+import (
+	"context"
+	"errors"
+	carTypes "github.com/ccsapp/cargotypes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const cachedVin = "WVWAA71K08W201030"
+const otherCachedVin = "WVWAA71K08W201031"
+
+// countingClient answers GetCar requests with car1 for any known VIN and counts the requests
+type countingClient struct {
+	ClientWithResponsesInterface
+	requests  int
+	knownVins map[carTypes.Vin]bool
+	err       error
+}
+
+func newCountingClient(knownVins ...carTypes.Vin) *countingClient {
+	client := &countingClient{knownVins: make(map[carTypes.Vin]bool)}
+	for _, vin := range knownVins {
+		client.knownVins[vin] = true
+	}
+	return client
+}
+
+func (c *countingClient) GetCarWithResponse(_ context.Context, vin carTypes.VinParam,
+	_ ...RequestEditorFn) (*GetCarResponse, error) {
+
+	c.requests++
+	if c.err != nil {
+		return nil, c.err
+	}
+	if !c.knownVins[vin] {
+		return &GetCarResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil
+	}
+
+	car := car1
+	car.Vin = vin
+	return &GetCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+		JSON200:      &car,
+	}, nil
+}
+
+func (c *countingClient) DeleteCarWithResponse(_ context.Context, vin carTypes.VinParam,
+	_ ...RequestEditorFn) (*DeleteCarResponse, error) {
+
+	delete(c.knownVins, vin)
+	return &DeleteCarResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+// newTestCache creates a CachingClient with a clock which is advanced by the returned function
+func newTestCache(client ClientWithResponsesInterface, config CacheConfig) (*CachingClient, func(time.Duration)) {
+	cache := NewCachingClient(client, config)
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time {
+		return now
+	}
+	return cache, func(duration time.Duration) {
+		now = now.Add(duration)
+	}
+}
+
+var testCacheConfig = CacheConfig{
+	StaticTTL:  time.Hour,
+	DynamicTTL: 10 * time.Second,
+	MaxEntries: 10,
+}
+
+func TestCachingClient_hit(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	first, err := cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Nil(t, err)
+	second, err := cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, client.requests)
+	assert.Equal(t, http.StatusOK, second.StatusCode())
+	assert.Equal(t, first.JSON200, second.JSON200)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Entries: 1}, cache.Stats())
+}
+
+func TestCachingClient_returnsCopies(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	first, _ := cache.GetCarWithResponse(context.Background(), cachedVin)
+	original := *first.JSON200
+	first.JSON200.Brand = "modified"
+	first.JSON200.DynamicData.Position.Latitude = -1
+	first.JSON200.TechnicalSpecification.Engine.Type = "modified"
+	first.HTTPResponse.Header.Set("X-Modified", "true")
+	second, _ := cache.GetCarWithResponse(context.Background(), cachedVin)
+
+	assert.Equal(t, original, *second.JSON200)
+	assert.Empty(t, second.HTTPResponse.Header.Get("X-Modified"))
+}
+
+func TestCachingClient_carContainsNoReferences(t *testing.T) {
+	// the cache hands out shallow copies of the cached cars, which are only independent as long as a car does not
+	// contain any references
+	var check func(path string, typ reflect.Type)
+	check = func(path string, typ reflect.Type) {
+		if typ == reflect.TypeOf(time.Time{}) {
+			// times are immutable values
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+			t.Errorf("%s is a %s, the cache has to copy it explicitly", path, typ.Kind())
+		case reflect.Array:
+			check(path+"[]", typ.Elem())
+		case reflect.Struct:
+			for i := 0; i < typ.NumField(); i++ {
+				check(path+"."+typ.Field(i).Name, typ.Field(i).Type)
+			}
+		}
+	}
+	check("Car", reflect.TypeOf(carTypes.Car{}))
+}
+
+func TestCachingClient_dynamicDataExpired(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, advance := newTestCache(client, testCacheConfig)
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	advance(time.Minute)
+
+	// callers which only need the static data are still served from the cache
+	response, err := cache.GetCarWithResponse(WithStaticDataSufficient(context.Background()), cachedVin)
+	assert.Nil(t, err)
+	assert.NotNil(t, response.JSON200)
+	assert.Equal(t, 1, client.requests)
+
+	// but others need the current dynamic data
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Equal(t, 2, client.requests)
+
+	// which refreshes the cached car
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Equal(t, 2, client.requests)
+}
+
+func TestCachingClient_staticDataExpired(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, advance := newTestCache(client, testCacheConfig)
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	advance(time.Hour)
+
+	_, _ = cache.GetCarWithResponse(WithStaticDataSufficient(context.Background()), cachedVin)
+	assert.Equal(t, 2, client.requests)
+}
+
+func TestCachingClient_notFoundNotCached(t *testing.T) {
+	client := newCountingClient()
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	for i := 0; i < 2; i++ {
+		response, err := cache.GetCarWithResponse(context.Background(), cachedVin)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode())
+	}
+
+	assert.Equal(t, 2, client.requests)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestCachingClient_errorNotCached(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	client.err = errors.New("connection refused")
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	response, err := cache.GetCarWithResponse(context.Background(), cachedVin)
+
+	assert.ErrorIs(t, err, client.err)
+	assert.Nil(t, response)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestCachingClient_evictsLeastRecentlyUsed(t *testing.T) {
+	client := newCountingClient(cachedVin, otherCachedVin, car1.Vin)
+	config := testCacheConfig
+	config.MaxEntries = 2
+	cache, _ := newTestCache(client, config)
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	_, _ = cache.GetCarWithResponse(context.Background(), otherCachedVin)
+	// cachedVin is used again, so otherCachedVin is the least recently used car
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	_, _ = cache.GetCarWithResponse(context.Background(), car1.Vin)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3, Evictions: 1, Entries: 2}, cache.Stats())
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Equal(t, 3, client.requests)
+	_, _ = cache.GetCarWithResponse(context.Background(), otherCachedVin)
+	assert.Equal(t, 4, client.requests)
+}
+
+func TestCachingClient_invalidate(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	cache.Invalidate(cachedVin)
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+
+	assert.Equal(t, 2, client.requests)
+}
+
+func TestCachingClient_deleteInvalidates(t *testing.T) {
+	client := newCountingClient(cachedVin)
+	cache, _ := newTestCache(client, testCacheConfig)
+
+	_, _ = cache.GetCarWithResponse(context.Background(), cachedVin)
+	_, err := cache.DeleteCarWithResponse(context.Background(), cachedVin)
+	assert.Nil(t, err)
+
+	response, _ := cache.GetCarWithResponse(context.Background(), cachedVin)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+}
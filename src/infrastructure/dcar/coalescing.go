@@ -0,0 +1,44 @@
+package dcar
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"context"
+	carTypes "github.com/ccsapp/cargotypes"
+)
+
+// CoalescingClient decorates a ClientWithResponsesInterface so that concurrent GetCarWithResponse calls for the same
+// VIN share a single request to the Car service. All other requests are forwarded.
+type CoalescingClient struct {
+	ClientWithResponsesInterface
+
+	getCar resilience.Coalescer[*GetCarResponse]
+}
+
+// NewCoalescingClient wraps the given client to coalesce concurrent requests for the same car
+func NewCoalescingClient(client ClientWithResponsesInterface) *CoalescingClient {
+	return &CoalescingClient{ClientWithResponsesInterface: client}
+}
+
+func (c *CoalescingClient) GetCarWithResponse(ctx context.Context, vin carTypes.VinParam,
+	reqEditors ...RequestEditorFn) (*GetCarResponse, error) {
+
+	response, err := c.getCar.Do(ctx, vin, func(ctx context.Context) (*GetCarResponse, error) {
+		return c.ClientWithResponsesInterface.GetCarWithResponse(ctx, vin, reqEditors...)
+	})
+	if err != nil || response == nil {
+		return response, err
+	}
+
+	// every caller gets its own copy of the car so that the shared response cannot be modified
+	shared := *response
+	if response.JSON200 != nil {
+		car := *response.JSON200
+		shared.JSON200 = &car
+	}
+	return &shared, nil
+}
+
+// Stats returns the current counters of the coalesced GetCarWithResponse calls
+func (c *CoalescingClient) Stats() resilience.CoalescingStats {
+	return c.getCar.Stats()
+}
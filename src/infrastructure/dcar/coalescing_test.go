@@ -0,0 +1,50 @@
+package dcar
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"context"
+	carTypes "github.com/ccsapp/cargotypes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// sharedResponseClient answers every GetCar request with the same response, like a coalesced call
+type sharedResponseClient struct {
+	ClientWithResponsesInterface
+	response *GetCarResponse
+}
+
+func (c sharedResponseClient) GetCarWithResponse(context.Context, carTypes.VinParam,
+	...RequestEditorFn) (*GetCarResponse, error) {
+
+	return c.response, nil
+}
+
+func TestCoalescingClient_returnsCopies(t *testing.T) {
+	car := car1
+	coalescing := NewCoalescingClient(sharedResponseClient{response: &GetCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &car,
+	}})
+
+	first, err := coalescing.GetCarWithResponse(context.Background(), car1.Vin)
+	assert.Nil(t, err)
+	first.JSON200.Brand = "modified"
+	second, err := coalescing.GetCarWithResponse(context.Background(), car1.Vin)
+	assert.Nil(t, err)
+
+	assert.Equal(t, car1.Brand, second.JSON200.Brand)
+	assert.Equal(t, car1.Brand, car.Brand)
+	assert.Equal(t, resilience.CoalescingStats{Forwarded: 2}, coalescing.Stats())
+}
+
+func TestCoalescingClient_notFound(t *testing.T) {
+	coalescing := NewCoalescingClient(newCountingClient())
+
+	response, err := coalescing.GetCarWithResponse(context.Background(), cachedVin)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+	assert.Nil(t, response.JSON200)
+}
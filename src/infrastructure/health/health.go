@@ -0,0 +1,123 @@
+// Package health provides the liveness and readiness endpoints probed by the orchestrator of this service.
+package health
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The paths of the endpoints, which are not part of the API
+const (
+	// LivenessPath responds as long as the service is able to handle requests at all
+	LivenessPath = "/healthz"
+
+	// ReadinessPath responds with success only if all dependencies of the service are available
+	ReadinessPath = "/readyz"
+)
+
+// The status of the service or of one of its dependencies
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check checks whether a dependency is available and fails otherwise. It has to stop when the context is done.
+type Check func(ctx context.Context) error
+
+// DependencyStatus is the result of the Check of a dependency
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the result of the readiness probe: the service is only up if all of its dependencies are up
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// Checker checks the dependencies of the service
+type Checker struct {
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// NewChecker creates a Checker which fails the check of a dependency if it takes longer than the given timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  map[string]Check{},
+		timeout: timeout,
+	}
+}
+
+// Add adds the Check of the dependency with the given name, which is reported with this name
+func (c *Checker) Add(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Check runs the checks of all dependencies concurrently and reports their status
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Status:       StatusUp,
+		Dependencies: make(map[string]DependencyStatus, len(c.checks)),
+	}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			status := DependencyStatus{Status: StatusUp}
+			if err := check(ctx); err != nil {
+				status = DependencyStatus{Status: StatusDown, Error: err.Error()}
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Dependencies[name] = status
+			if status.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+// Register adds the liveness and the readiness endpoint to the given Echo instance. The readiness endpoint responds
+// with 503 if any dependency is down.
+func (c *Checker) Register(e *echo.Echo) {
+	e.GET(LivenessPath, func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusOK, Report{Status: StatusUp})
+	})
+	e.GET(ReadinessPath, func(ctx echo.Context) error {
+		report := c.Check(ctx.Request().Context())
+		if report.Status != StatusUp {
+			return ctx.JSON(http.StatusServiceUnavailable, report)
+		}
+		return ctx.JSON(http.StatusOK, report)
+	})
+}
+
+// ServerCheck creates a Check whether the server at the given URL is reachable with the given client. Any response
+// counts as reachable, as the server is not expected to serve anything at its base URL.
+func ServerCheck(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			return fmt.Errorf("unreachable: %w", err)
+		}
+		return response.Body.Close()
+	}
+}
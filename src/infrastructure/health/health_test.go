@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// probe requests the given path of the given Echo instance and returns the status code and the decoded report
+func probe(t *testing.T, e *echo.Echo, path string) (int, Report) {
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	return recorder.Code, report
+}
+
+func TestChecker_liveness(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+	e := echo.New()
+	checker.Register(e)
+
+	// the dependencies are not checked
+	status, report := probe(t, e, LivenessPath)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, Report{Status: StatusUp}, report)
+}
+
+func TestChecker_ready(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(context.Context) error {
+		return nil
+	})
+	checker.Add("car", ServerCheck(server.Client(), server.URL))
+	e := echo.New()
+	checker.Register(e)
+
+	status, report := probe(t, e, ReadinessPath)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, Report{
+		Status: StatusUp,
+		Dependencies: map[string]DependencyStatus{
+			"database": {Status: StatusUp},
+			"car":      {Status: StatusUp},
+		},
+	}, report)
+}
+
+func TestChecker_notReady(t *testing.T) {
+	// a server which is closed is not reachable
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+	checker.Add("car", func(context.Context) error {
+		return nil
+	})
+	checker.Add("rentalManagement", ServerCheck(http.DefaultClient, server.URL))
+	e := echo.New()
+	checker.Register(e)
+
+	status, report := probe(t, e, ReadinessPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, DependencyStatus{Status: StatusDown, Error: "connection refused"}, report.Dependencies["database"])
+	assert.Equal(t, DependencyStatus{Status: StatusUp}, report.Dependencies["car"])
+	assert.Equal(t, StatusDown, report.Dependencies["rentalManagement"].Status)
+	assert.Contains(t, report.Dependencies["rentalManagement"].Error, "unreachable")
+}
+
+func TestChecker_timeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Add("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, DependencyStatus{Status: StatusDown, Error: context.DeadlineExceeded.Error()},
+		report.Dependencies["database"])
+}
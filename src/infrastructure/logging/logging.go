@@ -0,0 +1,86 @@
+// Package logging writes structured access logs and correlates the requests of this service with the requests it
+// sends to the downstream services by their request ID.
+package logging
+
+import (
+	"PFleetManagement/logic/caller"
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// accessLogEntry is written as a single JSON line for every handled request
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestID,omitempty"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	URI       string    `json:"uri"`
+	FleetID   string    `json:"fleetID,omitempty"`
+	Vin       string    `json:"vin,omitempty"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// AccessLog creates a middleware which writes an access log entry for every request to the given writer as a JSON
+// line, containing the method, the route, the fleet ID and VIN (if the route has them), the status code, the latency
+// and the request ID. The request ID is read from the X-Request-ID response header, which has to be set by a
+// following middleware (e.g. middleware.RequestID). It has to precede all other middlewares which may reject requests
+// so that their responses are logged as well.
+func AccessLog(out io.Writer) echo.MiddlewareFunc {
+	var mutex sync.Mutex
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:   true,
+		LogMethod:    true,
+		LogRoutePath: true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogError:     true,
+		LogRequestID: true,
+		// pass errors to the error handler before logging, as the status code is unknown before
+		HandleError: true,
+		LogValuesFunc: func(ctx echo.Context, values middleware.RequestLoggerValues) error {
+			entry := accessLogEntry{
+				Time:      values.StartTime.UTC(),
+				RequestID: values.RequestID,
+				Method:    values.Method,
+				Route:     values.RoutePath,
+				URI:       values.URI,
+				FleetID:   ctx.Param("fleetID"),
+				Vin:       ctx.Param("vin"),
+				Status:    values.Status,
+				LatencyMs: float64(values.Latency.Microseconds()) / 1000,
+			}
+			if values.Error != nil {
+				entry.Error = values.Error.Error()
+			}
+
+			line, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+
+			// write every entry at once, so that the entries of concurrent requests are not interleaved
+			mutex.Lock()
+			defer mutex.Unlock()
+			_, err = out.Write(append(line, '\n'))
+			return err
+		},
+	})
+}
+
+// ForwardRequestID sets the X-Request-ID header of the given request to the ID of the request currently handled
+// (see caller.Info), so that the logs of the downstream service can be correlated with the logs of this service. It
+// matches the RequestEditorFn of the generated clients.
+func ForwardRequestID(ctx context.Context, req *http.Request) error {
+	if requestID := caller.FromContext(ctx).RequestID; requestID != "" {
+		req.Header.Set(echo.HeaderXRequestID, requestID)
+	}
+	return nil
+}
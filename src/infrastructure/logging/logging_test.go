@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"PFleetManagement/logic/caller"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readEntries decodes the access log entries written to the given buffer
+func readEntries(t *testing.T, out *bytes.Buffer) []accessLogEntry {
+	var entries []accessLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry accessLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	e := echo.New()
+	e.Use(AccessLog(&out))
+	e.Use(middleware.RequestID())
+	e.GET("/fleets/:fleetID/cars/:vin", func(ctx echo.Context) error {
+		if ctx.Param("vin") == "WVWAA71K08W201031" {
+			return echo.NewHTTPError(http.StatusNotFound, "no such car")
+		}
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/fleets/jJd9jb8I/cars/WVWAA71K08W201030?x=1", nil)
+	request.Header.Set(echo.HeaderXRequestID, "given-id")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+	response := httptest.NewRecorder()
+	e.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/fleets/jJd9jb8I/cars/WVWAA71K08W201031", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+
+	entries := readEntries(t, &out)
+	assert.Len(t, entries, 3)
+
+	// the ID given by the client is kept
+	assert.Equal(t, "given-id", entries[0].RequestID)
+	assert.Equal(t, http.MethodGet, entries[0].Method)
+	assert.Equal(t, "/fleets/:fleetID/cars/:vin", entries[0].Route)
+	assert.Equal(t, "/fleets/jJd9jb8I/cars/WVWAA71K08W201030?x=1", entries[0].URI)
+	assert.Equal(t, "jJd9jb8I", entries[0].FleetID)
+	assert.Equal(t, "WVWAA71K08W201030", entries[0].Vin)
+	assert.Equal(t, http.StatusOK, entries[0].Status)
+	assert.Empty(t, entries[0].Error)
+
+	// a generated ID is logged as it is responded
+	assert.Equal(t, response.Header().Get(echo.HeaderXRequestID), entries[1].RequestID)
+	assert.NotEmpty(t, entries[1].RequestID)
+	assert.Equal(t, http.StatusNotFound, entries[1].Status)
+	assert.Contains(t, entries[1].Error, "no such car")
+
+	assert.Equal(t, http.StatusNotFound, entries[2].Status)
+	assert.Empty(t, entries[2].FleetID)
+}
+
+func TestForwardRequestID(t *testing.T) {
+	ctx := caller.WithInfo(context.Background(), caller.Info{RequestID: "given-id"})
+	request := httptest.NewRequest(http.MethodGet, "/cars/WVWAA71K08W201030", nil)
+
+	assert.Nil(t, ForwardRequestID(ctx, request))
+	assert.Equal(t, "given-id", request.Header.Get(echo.HeaderXRequestID))
+}
+
+func TestForwardRequestID_noRequestID(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/cars/WVWAA71K08W201030", nil)
+
+	assert.Nil(t, ForwardRequestID(context.Background(), request))
+	assert.Empty(t, request.Header.Get(echo.HeaderXRequestID))
+}
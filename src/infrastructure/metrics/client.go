@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// errorStatus is the status label of requests to downstream services which did not receive a response
+const errorStatus = "error"
+
+// instrumentedDoer records the duration and the status code of the requests of another HttpRequestDoer
+type instrumentedDoer struct {
+	doer    resilience.HttpRequestDoer
+	metrics *Metrics
+	service string
+}
+
+// InstrumentDoer wraps the given HttpRequestDoer of the client of the given downstream service (e.g. "car") so that
+// the duration of its requests is recorded by method and status code. If the doer retries requests, the total
+// duration of all attempts is recorded together with the final outcome.
+func (m *Metrics) InstrumentDoer(service string, doer resilience.HttpRequestDoer) resilience.HttpRequestDoer {
+	return &instrumentedDoer{
+		doer:    doer,
+		metrics: m,
+		service: service,
+	}
+}
+
+func (d *instrumentedDoer) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := d.doer.Do(req)
+
+	status := errorStatus
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	d.metrics.clientRequestDuration.
+		WithLabelValues(d.service, req.Method, status).
+		Observe(time.Since(start).Seconds())
+
+	return response, err
+}
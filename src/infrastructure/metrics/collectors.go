@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/infrastructure/dcar"
+	"PFleetManagement/infrastructure/resilience"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// fleetSizeTimeout limits the duration of the database query for the fleet sizes during a scrape
+const fleetSizeTimeout = 5 * time.Second
+
+// cacheCollector reads the counters of a car cache when the metrics are scraped
+type cacheCollector struct {
+	stats func() dcar.CacheStats
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	entries   *prometheus.Desc
+}
+
+// RegisterCarCache exposes the counters of the car cache which are returned by the given function (typically
+// dcar.CachingClient.Stats)
+func (m *Metrics) RegisterCarCache(stats func() dcar.CacheStats) {
+	name := func(name string) string {
+		return prometheus.BuildFQName(namespace, "car_cache", name)
+	}
+	m.registry.MustRegister(&cacheCollector{
+		stats:     stats,
+		hits:      prometheus.NewDesc(name("hits_total"), "Number of car requests answered from the cache.", nil, nil),
+		misses:    prometheus.NewDesc(name("misses_total"), "Number of car requests forwarded to the Car service.", nil, nil),
+		evictions: prometheus.NewDesc(name("evictions_total"), "Number of cars evicted to satisfy the size limit.", nil, nil),
+		entries:   prometheus.NewDesc(name("entries"), "Number of currently cached cars.", nil, nil),
+	})
+}
+
+func (c *cacheCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.hits
+	descs <- c.misses
+	descs <- c.evictions
+	descs <- c.entries
+}
+
+func (c *cacheCollector) Collect(metrics chan<- prometheus.Metric) {
+	stats := c.stats()
+	metrics <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	metrics <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	metrics <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	metrics <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries))
+}
+
+// coalescingCollector reads the counters of the coalesced calls of a client when the metrics are scraped
+type coalescingCollector struct {
+	stats func() resilience.CoalescingStats
+
+	forwarded *prometheus.Desc
+	coalesced *prometheus.Desc
+}
+
+// RegisterCoalescing exposes the counters of the coalesced calls to the given downstream service (e.g. "car") which
+// are returned by the given function (typically the Stats method of a coalescing client)
+func (m *Metrics) RegisterCoalescing(service string, stats func() resilience.CoalescingStats) {
+	labels := prometheus.Labels{"service": service}
+	name := func(name string) string {
+		return prometheus.BuildFQName(namespace, "client", name)
+	}
+	m.registry.MustRegister(&coalescingCollector{
+		stats: stats,
+		forwarded: prometheus.NewDesc(name("forwarded_calls_total"),
+			"Number of coalescable calls sent to the downstream service.", nil, labels),
+		coalesced: prometheus.NewDesc(name("coalesced_calls_total"),
+			"Number of calls answered with the response of an identical concurrent call.", nil, labels),
+	})
+}
+
+func (c *coalescingCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.forwarded
+	descs <- c.coalesced
+}
+
+func (c *coalescingCollector) Collect(metrics chan<- prometheus.Metric) {
+	stats := c.stats()
+	metrics <- prometheus.MustNewConstMetric(c.forwarded, prometheus.CounterValue, float64(stats.Forwarded))
+	metrics <- prometheus.MustNewConstMetric(c.coalesced, prometheus.CounterValue, float64(stats.Coalesced))
+}
+
+// fleetSizeCollector queries the number of cars in every fleet when the metrics are scraped
+type fleetSizeCollector struct {
+	fleetDB database.FleetDB
+	size    *prometheus.Desc
+}
+
+// RegisterFleetSizes exposes the number of cars currently assigned to each fleet, which is read from the given
+// database.FleetDB on every scrape
+func (m *Metrics) RegisterFleetSizes(fleetDB database.FleetDB) {
+	m.registry.MustRegister(&fleetSizeCollector{
+		fleetDB: fleetDB,
+		size: prometheus.NewDesc(prometheus.BuildFQName(namespace, "fleet", "cars"),
+			"Number of cars currently assigned to the fleet.", []string{"fleet"}, nil),
+	})
+}
+
+func (c *fleetSizeCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.size
+}
+
+func (c *fleetSizeCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), fleetSizeTimeout)
+	defer cancel()
+
+	sizes, err := c.fleetDB.GetFleetSizes(ctx)
+	if err != nil {
+		metrics <- prometheus.NewInvalidMetric(c.size, err)
+		return
+	}
+	for fleetID, size := range sizes {
+		metrics <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(size), fleetID)
+	}
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/logic/model"
+	"context"
+	"time"
+)
+
+// instrumentedFleetDB records the duration and the outcome of the operations of another database.FleetDB
+type instrumentedFleetDB struct {
+	next    database.FleetDB
+	metrics *Metrics
+}
+
+// InstrumentFleetDB wraps the given database.FleetDB so that the duration of every operation is recorded by method
+// and outcome
+func (m *Metrics) InstrumentFleetDB(fleetDB database.FleetDB) database.FleetDB {
+	return &instrumentedFleetDB{
+		next:    fleetDB,
+		metrics: m,
+	}
+}
+
+// observe records an operation of the given method which started at the given time and failed if err is not nil
+func (d *instrumentedFleetDB) observe(method string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	d.metrics.databaseOperationDuration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+}
+
+func (d *instrumentedFleetDB) AddFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error) {
+	start := time.Now()
+	result, err := d.next.AddFleet(ctx, fleet)
+	d.observe("AddFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) UpdateFleet(ctx context.Context, fleetId model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
+
+	start := time.Now()
+	result, err := d.next.UpdateFleet(ctx, fleetId, update)
+	d.observe("UpdateFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetFleet(ctx context.Context, fleetId model.FleetID) (*model.Fleet, error) {
+	start := time.Now()
+	result, err := d.next.GetFleet(ctx, fleetId)
+	d.observe("GetFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	start := time.Now()
+	result, err := d.next.ListFleets(ctx)
+	d.observe("ListFleets", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetFleetSizes(ctx context.Context) (map[model.FleetID]int, error) {
+	start := time.Now()
+	result, err := d.next.GetFleetSizes(ctx)
+	d.observe("GetFleetSizes", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) DeleteFleet(ctx context.Context, fleetId model.FleetID) error {
+	start := time.Now()
+	err := d.next.DeleteFleet(ctx, fleetId)
+	d.observe("DeleteFleet", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) AddCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	start := time.Now()
+	err := d.next.AddCarToFleet(ctx, fleetId, vin)
+	d.observe("AddCarToFleet", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) AddCarsToFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) (map[model.Vin]error, error) {
+
+	start := time.Now()
+	result, err := d.next.AddCarsToFleet(ctx, fleetId, vins)
+	d.observe("AddCarsToFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) MoveCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin,
+	targetFleetId model.FleetID) error {
+
+	start := time.Now()
+	err := d.next.MoveCarToFleet(ctx, fleetId, vin, targetFleetId)
+	d.observe("MoveCarToFleet", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) RemoveCarFromFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	start := time.Now()
+	err := d.next.RemoveCarFromFleet(ctx, fleetId, vin)
+	d.observe("RemoveCarFromFleet", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) RemoveCarsFromFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) ([]model.Vin, error) {
+
+	start := time.Now()
+	result, err := d.next.RemoveCarsFromFleet(ctx, fleetId, vins)
+	d.observe("RemoveCarsFromFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	start := time.Now()
+	err := d.next.DecommissionCar(ctx, vin, reason)
+	d.observe("DecommissionCar", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) GetCarsForFleet(ctx context.Context, fleetId model.FleetID,
+	asOf *time.Time) ([]model.Vin, error) {
+
+	start := time.Now()
+	result, err := d.next.GetCarsForFleet(ctx, fleetId, asOf)
+	d.observe("GetCarsForFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetCarsForFleetPage(ctx context.Context, fleetId model.FleetID, asOf *time.Time,
+	order database.VinOrder, offset, limit int) ([]model.Vin, error) {
+
+	start := time.Now()
+	result, err := d.next.GetCarsForFleetPage(ctx, fleetId, asOf, order, offset, limit)
+	d.observe("GetCarsForFleetPage", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) IsCarInFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) (bool, error) {
+	start := time.Now()
+	result, err := d.next.IsCarInFleet(ctx, fleetId, vin)
+	d.observe("IsCarInFleet", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetFleetHistory(ctx context.Context, fleetId model.FleetID, from, to *time.Time,
+	offset, limit int) ([]model.AuditEntry, error) {
+
+	start := time.Now()
+	result, err := d.next.GetFleetHistory(ctx, fleetId, from, to, offset, limit)
+	d.observe("GetFleetHistory", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) SetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject,
+	role model.Role) error {
+
+	start := time.Now()
+	err := d.next.SetRole(ctx, fleetId, subject, role)
+	d.observe("SetRole", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) DeleteRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) error {
+	start := time.Now()
+	err := d.next.DeleteRole(ctx, fleetId, subject)
+	d.observe("DeleteRole", start, err)
+	return err
+}
+
+func (d *instrumentedFleetDB) GetRole(ctx context.Context, fleetId model.FleetID,
+	subject model.Subject) (*model.Role, error) {
+
+	start := time.Now()
+	result, err := d.next.GetRole(ctx, fleetId, subject)
+	d.observe("GetRole", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetRoles(ctx context.Context, subject model.Subject) (map[model.FleetID]model.Role, error) {
+	start := time.Now()
+	result, err := d.next.GetRoles(ctx, subject)
+	d.observe("GetRoles", start, err)
+	return result, err
+}
+
+func (d *instrumentedFleetDB) GetMembers(ctx context.Context, fleetId model.FleetID) ([]model.Membership, error) {
+	start := time.Now()
+	result, err := d.next.GetMembers(ctx, fleetId)
+	d.observe("GetMembers", start, err)
+	return result, err
+}
+
+// Ping is not recorded, as it is not part of handling requests but used by the readiness probe
+func (d *instrumentedFleetDB) Ping(ctx context.Context) error {
+	return d.next.Ping(ctx)
+}
+
+func (d *instrumentedFleetDB) CleanUpDatabase() error {
+	return d.next.CleanUpDatabase()
+}
+
+func (d *instrumentedFleetDB) DropCollection(ctx context.Context) error {
+	return d.next.DropCollection(ctx)
+}
@@ -0,0 +1,101 @@
+// Package metrics instruments this service, its database and the clients of the downstream services with Prometheus
+// metrics and exposes them to be scraped.
+package metrics
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// namespace prefixes the names of all metrics of this service
+const namespace = "fleet_management"
+
+// unmatchedRoute is the route label of requests which do not match any route (echo leaves the path empty for them)
+const unmatchedRoute = "unmatched"
+
+// Metrics holds the collectors of this service in its own registry, so that several instances (e.g. in tests) do not
+// interfere with each other
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequestDuration       *prometheus.HistogramVec
+	clientRequestDuration     *prometheus.HistogramVec
+	databaseOperationDuration *prometheus.HistogramVec
+}
+
+// New creates the collectors of this service and registers them together with the standard Go runtime and process
+// collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the handled HTTP requests by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		clientRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help: "Duration of the requests to the downstream services by service, method and status code " +
+				"(\"error\" if no response was received).",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"service", "method", "status"}),
+		databaseOperationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of the database operations by FleetDB method and outcome (\"success\" or \"error\").",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequestDuration,
+		m.clientRequestDuration,
+		m.databaseOperationDuration,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format. Collectors which fail (e.g. because the database
+// is unavailable) are reported as errors without failing the other ones.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Middleware records the duration of every request by method, route and status code. It has to precede all other
+// middlewares which may reject requests so that their responses are recorded as well.
+func (m *Metrics) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		start := time.Now()
+
+		// errors are handled here (instead of being returned) as the status code is unknown before
+		err := next(ctx)
+		if err != nil {
+			ctx.Error(err)
+		}
+
+		// the route is recorded instead of the path to keep the number of label values bounded
+		route := ctx.Path()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		m.httpRequestDuration.
+			WithLabelValues(ctx.Request().Method, route, strconv.Itoa(ctx.Response().Status)).
+			Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
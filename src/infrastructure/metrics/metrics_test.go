@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"PFleetManagement/infrastructure/dcar"
+	"PFleetManagement/infrastructure/resilience"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"PFleetManagement/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// scrape returns the metrics exposed by the handler in the text format
+func scrape(t *testing.T, m *Metrics) string {
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := New()
+	e := echo.New()
+	e.Use(m.Middleware)
+	e.GET("/fleets/:fleetID", func(ctx echo.Context) error {
+		if ctx.Param("fleetID") == "unknown1" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	for _, target := range []string{"/fleets/jJd9jb8I", "/fleets/xk48jpgz", "/fleets/unknown1", "/other"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics,
+		`fleet_management_http_request_duration_seconds_count{method="GET",route="/fleets/:fleetID",status="200"} 2`)
+	assert.Contains(t, metrics,
+		`fleet_management_http_request_duration_seconds_count{method="GET",route="/fleets/:fleetID",status="404"} 1`)
+	assert.Contains(t, metrics,
+		`fleet_management_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestMetrics_InstrumentDoer(t *testing.T) {
+	m := New()
+	doer := m.InstrumentDoer("car", doerFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/unavailable") {
+			return nil, fleetErrors.ErrServiceUnavailable
+		}
+		return &http.Response{StatusCode: http.StatusNotFound}, nil
+	}))
+
+	_, err := doer.Do(httptest.NewRequest(http.MethodGet, "/cars/WVWAA71K08W201030", nil))
+	assert.Nil(t, err)
+	_, err = doer.Do(httptest.NewRequest(http.MethodGet, "/cars/unavailable", nil))
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics,
+		`fleet_management_client_request_duration_seconds_count{method="GET",service="car",status="404"} 1`)
+	assert.Contains(t, metrics,
+		`fleet_management_client_request_duration_seconds_count{method="GET",service="car",status="error"} 1`)
+}
+
+func TestMetrics_InstrumentFleetDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	m := New()
+	fleetDB := m.InstrumentFleetDB(mockDB)
+
+	ctx := context.Background()
+	mockDB.EXPECT().GetFleet(ctx, "jJd9jb8I").Return(&model.Fleet{FleetID: "jJd9jb8I"}, nil)
+	mockDB.EXPECT().RemoveCarFromFleet(ctx, "jJd9jb8I", "WVWAA71K08W201030").Return(fleetErrors.ErrCarNotInFleet)
+
+	fleet, err := fleetDB.GetFleet(ctx, "jJd9jb8I")
+	assert.Nil(t, err)
+	assert.Equal(t, "jJd9jb8I", fleet.FleetID)
+
+	err = fleetDB.RemoveCarFromFleet(ctx, "jJd9jb8I", "WVWAA71K08W201030")
+	assert.ErrorIs(t, err, fleetErrors.ErrCarNotInFleet)
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics,
+		`fleet_management_database_operation_duration_seconds_count{method="GetFleet",outcome="success"} 1`)
+	assert.Contains(t, metrics,
+		`fleet_management_database_operation_duration_seconds_count{method="RemoveCarFromFleet",outcome="error"} 1`)
+}
+
+func TestMetrics_RegisterCarCache(t *testing.T) {
+	m := New()
+	m.RegisterCarCache(func() dcar.CacheStats {
+		return dcar.CacheStats{Hits: 7, Misses: 3, Evictions: 1, Entries: 2}
+	})
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics, "fleet_management_car_cache_hits_total 7\n")
+	assert.Contains(t, metrics, "fleet_management_car_cache_misses_total 3\n")
+	assert.Contains(t, metrics, "fleet_management_car_cache_evictions_total 1\n")
+	assert.Contains(t, metrics, "fleet_management_car_cache_entries 2\n")
+}
+
+func TestMetrics_RegisterCoalescing(t *testing.T) {
+	m := New()
+	m.RegisterCoalescing("car", func() resilience.CoalescingStats {
+		return resilience.CoalescingStats{Forwarded: 5, Coalesced: 12}
+	})
+	m.RegisterCoalescing("rentalManagement", func() resilience.CoalescingStats {
+		return resilience.CoalescingStats{Forwarded: 2}
+	})
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics, `fleet_management_client_forwarded_calls_total{service="car"} 5`)
+	assert.Contains(t, metrics, `fleet_management_client_coalesced_calls_total{service="car"} 12`)
+	assert.Contains(t, metrics, `fleet_management_client_forwarded_calls_total{service="rentalManagement"} 2`)
+	assert.Contains(t, metrics, `fleet_management_client_coalesced_calls_total{service="rentalManagement"} 0`)
+}
+
+func TestMetrics_RegisterFleetSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	m := New()
+	m.RegisterFleetSizes(mockDB)
+
+	mockDB.EXPECT().GetFleetSizes(gomock.Any()).Return(map[model.FleetID]int{"jJd9jb8I": 3, "xk48jpgz": 0}, nil)
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics, `fleet_management_fleet_cars{fleet="jJd9jb8I"} 3`)
+	assert.Contains(t, metrics, `fleet_management_fleet_cars{fleet="xk48jpgz"} 0`)
+}
+
+func TestMetrics_RegisterFleetSizes_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	m := New()
+	m.RegisterFleetSizes(mockDB)
+
+	mockDB.EXPECT().GetFleetSizes(gomock.Any()).Return(nil, errors.New("database error"))
+
+	// the other metrics are still exposed
+	metrics := scrape(t, m)
+	assert.NotContains(t, metrics, "fleet_management_fleet_cars")
+	assert.Contains(t, metrics, "go_goroutines")
+}
@@ -15,6 +15,9 @@ import (
 	"strings"
 )
 
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
 // HttpRequestDoer performs HTTP requests.
 // The standard http.Client implements this interface.
 type HttpRequestDoer interface {
@@ -32,6 +35,10 @@ type Client struct {
 	// Doer for performing requests, typically a *http.Client with any
 	// customized settings, such as certificate chains.
 	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
 }
 
 // ClientOption allows setting custom parameters during construction
@@ -69,18 +76,30 @@ func WithHTTPClient(doer HttpRequestDoer) ClientOption {
 	}
 }
 
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
 // ClientInterface specifies an interface for the client above.
 type ClientInterface interface {
 	// GetNextRental request
-	GetNextRental(ctx context.Context, vin model.VinParam) (*http.Response, error)
+	GetNextRental(ctx context.Context, vin model.VinParam, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
-func (c *Client) GetNextRental(ctx context.Context, vin model.VinParam) (*http.Response, error) {
+func (c *Client) GetNextRental(ctx context.Context, vin model.VinParam, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewGetNextRentalRequest(c.Server, vin)
 	if err != nil {
 		return nil, err
 	}
 	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
 	return c.Client.Do(req)
 }
 
@@ -118,6 +137,20 @@ func NewGetNextRentalRequest(server string, vin model.VinParam) (*http.Request,
 	return req, nil
 }
 
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ClientWithResponses builds on ClientInterface to offer response payloads
 type ClientWithResponses struct {
 	ClientInterface
@@ -136,7 +169,7 @@ func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithRes
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
 	// GetNextRentalWithResponse request
-	GetNextRentalWithResponse(ctx context.Context, vin model.VinParam) (*GetNextRentalResponse, error)
+	GetNextRentalWithResponse(ctx context.Context, vin model.VinParam, reqEditors ...RequestEditorFn) (*GetNextRentalResponse, error)
 }
 
 type GetNextRentalResponse struct {
@@ -162,8 +195,8 @@ func (r GetNextRentalResponse) StatusCode() int {
 }
 
 // GetNextRentalWithResponse request returning *GetNextRentalResponse
-func (c *ClientWithResponses) GetNextRentalWithResponse(ctx context.Context, vin model.VinParam) (*GetNextRentalResponse, error) {
-	rsp, err := c.GetNextRental(ctx, vin)
+func (c *ClientWithResponses) GetNextRentalWithResponse(ctx context.Context, vin model.VinParam, reqEditors ...RequestEditorFn) (*GetNextRentalResponse, error) {
+	rsp, err := c.GetNextRental(ctx, vin, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
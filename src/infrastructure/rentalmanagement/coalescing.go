@@ -0,0 +1,44 @@
+package rentalManagement
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"PFleetManagement/logic/model"
+	"context"
+)
+
+// CoalescingClient decorates a ClientWithResponsesInterface so that concurrent GetNextRentalWithResponse calls for
+// the same VIN share a single request to the RentalManagement service.
+type CoalescingClient struct {
+	client ClientWithResponsesInterface
+
+	getNextRental resilience.Coalescer[*GetNextRentalResponse]
+}
+
+// NewCoalescingClient wraps the given client to coalesce concurrent requests for the next rental of the same car
+func NewCoalescingClient(client ClientWithResponsesInterface) *CoalescingClient {
+	return &CoalescingClient{client: client}
+}
+
+func (c *CoalescingClient) GetNextRentalWithResponse(ctx context.Context, vin model.VinParam,
+	reqEditors ...RequestEditorFn) (*GetNextRentalResponse, error) {
+
+	response, err := c.getNextRental.Do(ctx, vin, func(ctx context.Context) (*GetNextRentalResponse, error) {
+		return c.client.GetNextRentalWithResponse(ctx, vin, reqEditors...)
+	})
+	if err != nil || response == nil {
+		return response, err
+	}
+
+	// every caller gets its own copy of the rental so that the shared response cannot be modified
+	shared := *response
+	if response.JSON200 != nil {
+		rental := *response.JSON200
+		shared.JSON200 = &rental
+	}
+	return &shared, nil
+}
+
+// Stats returns the current counters of the coalesced GetNextRentalWithResponse calls
+func (c *CoalescingClient) Stats() resilience.CoalescingStats {
+	return c.getNextRental.Stats()
+}
@@ -0,0 +1,85 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	// breakerClosed lets all requests pass and counts consecutive failures
+	breakerClosed breakerState = iota
+
+	// breakerOpen rejects all requests until the open duration has passed
+	breakerOpen
+
+	// breakerHalfOpen lets a single trial request pass which decides whether to close or reopen the breaker
+	breakerHalfOpen
+)
+
+// circuitBreaker protects a single host from requests while it is considered unavailable
+type circuitBreaker struct {
+	mutex               sync.Mutex
+	state               breakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+}
+
+// allow checks whether a request may be sent at the given time. If so, the result of the request must be reported
+// with done.
+func (b *circuitBreaker) allow(now time.Time, openDuration time.Duration) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if now.Sub(b.openedAt) < openDuration {
+			return false
+		}
+		// the host had time to recover -> try whether it is available again
+		b.state = breakerHalfOpen
+		b.trialInFlight = true
+		return true
+	case breakerHalfOpen:
+		// only a single trial request is sent at a time
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	}
+	return true
+}
+
+// done reports the result of a request which was allowed by the breaker at the given time
+func (b *circuitBreaker) done(now time.Time, success bool, failureThreshold int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if success {
+		b.state = breakerClosed
+		b.consecutiveFailures = 0
+		b.trialInFlight = false
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.state == breakerHalfOpen || b.consecutiveFailures >= failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = now
+		b.trialInFlight = false
+	}
+}
+
+// abort reports that a request which was allowed by the breaker ended without a result (e.g. it was cancelled)
+func (b *circuitBreaker) abort() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == breakerHalfOpen {
+		// another trial request may be sent
+		b.trialInFlight = false
+	}
+}
@@ -0,0 +1,147 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CoalescingStats are the counters of a Coalescer
+type CoalescingStats struct {
+	// Forwarded is the number of calls which were actually executed
+	Forwarded uint64
+
+	// Coalesced is the number of callers which received the result of a call executed for another caller
+	Coalesced uint64
+}
+
+// Coalescer shares the result of a call among all callers which make the call with the same key while it is in
+// flight, so that identical concurrent requests to a downstream service are only sent once. Its zero value is ready
+// to use.
+type Coalescer[T any] struct {
+	// the mutex guards the calls in flight and their waiters
+	mutex sync.Mutex
+	calls map[string]*coalescedCall[T]
+
+	forwarded atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// coalescedCall is a call in flight and the number of callers waiting for its result
+type coalescedCall[T any] struct {
+	waiters int
+	cancel  context.CancelFunc
+
+	// the result is written before done is closed
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Do executes the given call unless a call with the same key is already in flight, in which case its result is
+// returned instead. The call is executed with a context carrying the values of the context of the first caller, but
+// it is not canceled with it, as other callers may still wait for the result. Each caller stops waiting as soon as
+// its own context is done, and the call is canceled as soon as no caller waits for it any longer.
+func (c *Coalescer[T]) Do(ctx context.Context, key string, call func(ctx context.Context) (T, error)) (T, error) {
+	c.mutex.Lock()
+	inFlight, shared := c.calls[key]
+	if !shared {
+		inFlight = c.start(ctx, key, call)
+	}
+	inFlight.waiters++
+	c.mutex.Unlock()
+
+	select {
+	case <-inFlight.done:
+		if shared {
+			c.coalesced.Add(1)
+		}
+		return inFlight.value, inFlight.err
+	case <-ctx.Done():
+		c.leave(key, inFlight)
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// start executes the call with the given key in the background. The mutex must be held by the caller.
+func (c *Coalescer[T]) start(ctx context.Context, key string,
+	call func(ctx context.Context) (T, error)) *coalescedCall[T] {
+
+	callCtx, cancel := context.WithCancel(WithoutCancel(ctx))
+	inFlight := &coalescedCall[T]{cancel: cancel, done: make(chan struct{})}
+	if c.calls == nil {
+		c.calls = make(map[string]*coalescedCall[T])
+	}
+	c.calls[key] = inFlight
+	c.forwarded.Add(1)
+
+	go func() {
+		defer cancel()
+		inFlight.value, inFlight.err = call(callCtx)
+
+		c.mutex.Lock()
+		c.forget(key, inFlight)
+		c.mutex.Unlock()
+		close(inFlight.done)
+	}()
+	return inFlight
+}
+
+// leave stops waiting for the given call and cancels it if no other caller waits for it
+func (c *Coalescer[T]) leave(key string, inFlight *coalescedCall[T]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	inFlight.waiters--
+	if inFlight.waiters == 0 {
+		// later callers start a new call instead of joining the canceled one
+		c.forget(key, inFlight)
+		inFlight.cancel()
+	}
+}
+
+// forget removes the given call from the calls in flight unless it has been replaced already. The mutex must be held
+// by the caller.
+func (c *Coalescer[T]) forget(key string, inFlight *coalescedCall[T]) {
+	if c.calls[key] == inFlight {
+		delete(c.calls, key)
+	}
+}
+
+// Stats returns the current counters of the Coalescer
+func (c *Coalescer[T]) Stats() CoalescingStats {
+	return CoalescingStats{
+		Forwarded: c.forwarded.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
+
+// WithoutCancel returns a context which keeps the values (e.g. the trace and the request ID) of the given context,
+// but is neither canceled with it nor has its deadline. It allows finishing work which must not be abandoned even if
+// the caller gives up.
+func WithoutCancel(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// detachedContext is the context returned by WithoutCancel
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
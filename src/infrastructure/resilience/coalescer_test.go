@@ -0,0 +1,193 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type contextKey struct{}
+
+// blockingCall returns a call which counts its executions and blocks until the returned channel is closed
+func blockingCall(executions *atomic.Int32, result string, err error) (func(context.Context) (string, error),
+	chan struct{}) {
+
+	release := make(chan struct{})
+	return func(ctx context.Context) (string, error) {
+		executions.Add(1)
+		<-release
+		return result, err
+	}, release
+}
+
+// callConcurrently makes the call with the given key from the given number of goroutines, releases it after all of
+// them are waiting and returns their results
+func callConcurrently(coalescer *Coalescer[string], key string, callers int, call func(context.Context) (string, error),
+	release chan struct{}) ([]string, []error) {
+
+	results := make([]string, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	for index := 0; index < callers; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			results[index], errs[index] = coalescer.Do(context.Background(), key, call)
+		}(index)
+	}
+
+	// give all callers the chance to join the call before it returns
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return results, errs
+}
+
+func TestCoalescer_sharesResult(t *testing.T) {
+	var coalescer Coalescer[string]
+	var executions atomic.Int32
+	call, release := blockingCall(&executions, "car", nil)
+
+	results, errs := callConcurrently(&coalescer, "WVWAA71K08W201030", 5, call, release)
+
+	assert.Equal(t, int32(1), executions.Load())
+	assert.Equal(t, []string{"car", "car", "car", "car", "car"}, results)
+	assert.Equal(t, []error{nil, nil, nil, nil, nil}, errs)
+	assert.Equal(t, CoalescingStats{Forwarded: 1, Coalesced: 4}, coalescer.Stats())
+}
+
+func TestCoalescer_sharesError(t *testing.T) {
+	var coalescer Coalescer[string]
+	var executions atomic.Int32
+	callErr := errors.New("connection refused")
+	call, release := blockingCall(&executions, "", callErr)
+
+	_, errs := callConcurrently(&coalescer, "WVWAA71K08W201030", 3, call, release)
+
+	assert.Equal(t, int32(1), executions.Load())
+	for _, err := range errs {
+		assert.ErrorIs(t, err, callErr)
+	}
+}
+
+func TestCoalescer_sequentialCallsNotCoalesced(t *testing.T) {
+	var coalescer Coalescer[string]
+	call := func(context.Context) (string, error) {
+		return "car", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := coalescer.Do(context.Background(), "WVWAA71K08W201030", call)
+		assert.Nil(t, err)
+		assert.Equal(t, "car", result)
+	}
+
+	assert.Equal(t, CoalescingStats{Forwarded: 3}, coalescer.Stats())
+}
+
+func TestCoalescer_differentKeysNotCoalesced(t *testing.T) {
+	var coalescer Coalescer[string]
+	var executions atomic.Int32
+	call, release := blockingCall(&executions, "car", nil)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"WVWAA71K08W201030", "WVWAA71K08W201031"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			_, _ = coalescer.Do(context.Background(), key, call)
+		}(key)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), executions.Load())
+	assert.Equal(t, CoalescingStats{Forwarded: 2}, coalescer.Stats())
+}
+
+// waiters returns the number of callers waiting for the call with the given key
+func waiters(coalescer *Coalescer[string], key string) int {
+	coalescer.mutex.Lock()
+	defer coalescer.mutex.Unlock()
+	if inFlight, ok := coalescer.calls[key]; ok {
+		return inFlight.waiters
+	}
+	return 0
+}
+
+func TestCoalescer_callerCanceled(t *testing.T) {
+	var coalescer Coalescer[string]
+	var executions atomic.Int32
+	call, release := blockingCall(&executions, "car", nil)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "request"))
+	var callCtx context.Context
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := coalescer.Do(ctx, "WVWAA71K08W201030", func(ctx context.Context) (string, error) {
+			callCtx = ctx
+			close(started)
+			return call(ctx)
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	<-started
+
+	type result struct {
+		value string
+		err   error
+	}
+	results := make(chan result)
+	go func() {
+		value, err := coalescer.Do(context.Background(), "WVWAA71K08W201030", call)
+		results <- result{value, err}
+	}()
+	assert.Eventually(t, func() bool {
+		return waiters(&coalescer, "WVWAA71K08W201030") == 2
+	}, time.Second, time.Millisecond)
+
+	// the first caller gives up, but the call is continued for the other one
+	cancel()
+	<-done
+	assert.Nil(t, callCtx.Err())
+	assert.Equal(t, "request", callCtx.Value(contextKey{}))
+
+	close(release)
+	assert.Equal(t, result{"car", nil}, <-results)
+	assert.Equal(t, int32(1), executions.Load())
+	assert.Equal(t, CoalescingStats{Forwarded: 1, Coalesced: 1}, coalescer.Stats())
+}
+
+func TestCoalescer_onlyCallerCanceled(t *testing.T) {
+	var coalescer Coalescer[string]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callCanceled := make(chan error, 1)
+	call := func(callCtx context.Context) (string, error) {
+		// the only caller gives up while the call is in flight, so the call is canceled as well
+		cancel()
+		<-callCtx.Done()
+		callCanceled <- callCtx.Err()
+		return "", callCtx.Err()
+	}
+
+	_, err := coalescer.Do(ctx, "WVWAA71K08W201030", call)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, <-callCanceled, context.Canceled)
+
+	// later callers do not join the canceled call
+	result, err := coalescer.Do(context.Background(), "WVWAA71K08W201030", func(context.Context) (string, error) {
+		return "car", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "car", result)
+	assert.Equal(t, CoalescingStats{Forwarded: 2}, coalescer.Stats())
+}
@@ -0,0 +1,178 @@
+// Package resilience provides a wrapper for the HTTP clients of the downstream services which retries failed
+// requests and stops sending requests to hosts which are unavailable.
+package resilience
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HttpRequestDoer performs HTTP requests like the standard http.Client.
+// It is compatible with the HttpRequestDoer interfaces of the generated clients.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config configures the retries and the circuit breakers of a Doer
+type Config struct {
+	// MaxRetries is the maximum number of times a failed idempotent request is repeated. Zero disables retries.
+	MaxRetries int
+
+	// BaseBackoff is the maximum delay before the first retry. It is doubled for every further retry.
+	BaseBackoff time.Duration
+
+	// MaxBackoff limits the maximum delay before a retry. It must not be smaller than BaseBackoff.
+	MaxBackoff time.Duration
+
+	// FailureThreshold is the number of consecutive failed requests to a host after which the circuit breaker of
+	// the host opens. Zero disables the circuit breakers.
+	FailureThreshold int
+
+	// OpenDuration is the duration for which an open circuit breaker rejects requests before a trial request is sent
+	OpenDuration time.Duration
+}
+
+// Doer is an HttpRequestDoer which decorates another one with retries and per-host circuit breakers.
+//
+// Idempotent (GET and HEAD) requests are retried with exponential backoff and full jitter if they fail with a
+// connection error or a status code indicating a transient failure (429, 502, 503 and 504). If a host fails
+// repeatedly, its circuit breaker opens and requests to it fail immediately with fleetErrors.ErrServiceUnavailable.
+type Doer struct {
+	doer   HttpRequestDoer
+	config Config
+
+	now    func() time.Time
+	random func(n int64) int64
+	sleep  func(ctx context.Context, duration time.Duration) error
+
+	mutex    sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewDoer wraps the given HttpRequestDoer (typically an *http.Client) with retries and circuit breakers
+func NewDoer(doer HttpRequestDoer, config Config) *Doer {
+	return &Doer{
+		doer:     doer,
+		config:   config,
+		now:      time.Now,
+		random:   rand.Int63n,
+		sleep:    sleepContext,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// sleepContext waits for the given duration but returns early with the error of the context if it is done before
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Doer) Do(req *http.Request) (*http.Response, error) {
+	breaker := d.breaker(req.URL.Host)
+
+	attempts := 1
+	if isIdempotent(req) {
+		attempts += d.config.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !breaker.allow(d.now(), d.config.OpenDuration) {
+			return nil, fmt.Errorf("%w: circuit breaker for %s is open", fleetErrors.ErrServiceUnavailable, req.URL.Host)
+		}
+
+		response, err := d.doer.Do(req)
+		if req.Context().Err() != nil {
+			// the request was cancelled by the caller, which does not tell anything about the host
+			breaker.abort()
+			return response, err
+		}
+
+		failed := isFailure(response, err)
+		breaker.done(d.now(), !failed, d.failureThreshold())
+
+		if !failed || attempt+1 >= attempts {
+			return response, err
+		}
+
+		// the response of the failed attempt is discarded, so its connection can be reused
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+
+		if err := d.sleep(req.Context(), d.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// breaker returns the circuit breaker of the given host
+func (d *Doer) breaker(host string) *circuitBreaker {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	breaker, ok := d.breakers[host]
+	if !ok {
+		breaker = &circuitBreaker{}
+		d.breakers[host] = breaker
+	}
+	return breaker
+}
+
+// failureThreshold returns the configured failure threshold or one which is never reached if breakers are disabled
+func (d *Doer) failureThreshold() int {
+	if d.config.FailureThreshold <= 0 {
+		return int(^uint(0) >> 1)
+	}
+	return d.config.FailureThreshold
+}
+
+// backoff returns a random delay before the retry following the given (zero-based) attempt
+func (d *Doer) backoff(attempt int) time.Duration {
+	if d.config.BaseBackoff <= 0 {
+		return 0
+	}
+
+	maxDelay := d.config.BaseBackoff << attempt
+	if maxDelay <= 0 || maxDelay > d.config.MaxBackoff {
+		// (a non-positive delay results from an overflow)
+		maxDelay = d.config.MaxBackoff
+	}
+
+	// full jitter: spread retries of concurrent requests evenly over the whole interval
+	return time.Duration(d.random(int64(maxDelay) + 1))
+}
+
+// isIdempotent checks whether the request may be repeated without changing the outcome.
+// Only requests without a body are considered as such.
+func isIdempotent(req *http.Request) bool {
+	return (req.Method == http.MethodGet || req.Method == http.MethodHead) &&
+		(req.Body == nil || req.Body == http.NoBody)
+}
+
+// isFailure checks whether the outcome of a request indicates a (possibly transient) failure of the host
+func isFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package resilience
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testConfig = Config{
+	MaxRetries:       2,
+	BaseBackoff:      100 * time.Millisecond,
+	MaxBackoff:       time.Second,
+	FailureThreshold: 3,
+	OpenDuration:     30 * time.Second,
+}
+
+// stubServer is an httptest stand-in for a downstream service answering with the given status codes in order
+// (repeating the last one) and counting the requests
+type stubServer struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+func newStubServer(t *testing.T, statusCodes ...int) *stubServer {
+	server := &stubServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		index := int(server.requests.Add(1)) - 1
+		if index >= len(statusCodes) {
+			index = len(statusCodes) - 1
+		}
+		writer.WriteHeader(statusCodes[index])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestDoer creates a Doer which does not actually sleep but records the delays and whose clock is advanced by
+// the returned function
+func newTestDoer(config Config) (*Doer, *[]time.Duration, func(time.Duration)) {
+	doer := NewDoer(&http.Client{}, config)
+
+	var delays []time.Duration
+	doer.sleep = func(_ context.Context, duration time.Duration) error {
+		delays = append(delays, duration)
+		return nil
+	}
+	// always use the maximum delay to make the backoff predictable
+	doer.random = func(n int64) int64 {
+		return n - 1
+	}
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	doer.now = func() time.Time {
+		return now
+	}
+
+	return doer, &delays, func(duration time.Duration) {
+		now = now.Add(duration)
+	}
+}
+
+func get(t *testing.T, doer *Doer, url string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := doer.Do(request)
+	if response != nil {
+		_ = response.Body.Close()
+	}
+	return response, err
+}
+
+func TestDoer_successWithoutRetry(t *testing.T) {
+	server := newStubServer(t, http.StatusOK)
+	doer, delays, _ := newTestDoer(testConfig)
+
+	response, err := get(t, doer, server.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int32(1), server.requests.Load())
+	assert.Empty(t, *delays)
+}
+
+func TestDoer_retriesTransientFailure(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	doer, delays, _ := newTestDoer(testConfig)
+
+	response, err := get(t, doer, server.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int32(3), server.requests.Load())
+	// the backoff is doubled for every retry
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *delays)
+}
+
+func TestDoer_retriesExhausted(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	doer, _, _ := newTestDoer(testConfig)
+
+	response, err := get(t, doer, server.URL)
+
+	// the last response is returned to be handled by the caller
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Equal(t, int32(3), server.requests.Load())
+}
+
+func TestDoer_noRetryForClientError(t *testing.T) {
+	server := newStubServer(t, http.StatusNotFound, http.StatusOK)
+	doer, _, _ := newTestDoer(testConfig)
+
+	response, err := get(t, doer, server.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, int32(1), server.requests.Load())
+}
+
+func TestDoer_noRetryForNonIdempotentRequest(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	doer, _, _ := newTestDoer(testConfig)
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+	response, err := doer.Do(request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Equal(t, int32(1), server.requests.Load())
+}
+
+func TestDoer_retriesConnectionError(t *testing.T) {
+	server := newStubServer(t, http.StatusOK)
+	server.Close()
+	doer, delays, _ := newTestDoer(testConfig)
+
+	_, err := get(t, doer, server.URL)
+
+	assert.NotNil(t, err)
+	assert.Len(t, *delays, 2)
+}
+
+func TestDoer_backoffLimited(t *testing.T) {
+	doer, _, _ := newTestDoer(testConfig)
+
+	assert.Equal(t, 400*time.Millisecond, doer.backoff(2))
+	assert.Equal(t, time.Second, doer.backoff(4))
+	assert.Equal(t, time.Second, doer.backoff(100))
+}
+
+func TestDoer_backoffJitter(t *testing.T) {
+	doer := NewDoer(&http.Client{}, testConfig)
+
+	for i := 0; i < 100; i++ {
+		delay := doer.backoff(1)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, 200*time.Millisecond)
+	}
+}
+
+func TestDoer_circuitBreakerOpens(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	config := testConfig
+	config.MaxRetries = 0
+	doer, _, advance := newTestDoer(config)
+
+	for i := 0; i < config.FailureThreshold; i++ {
+		_, _ = get(t, doer, server.URL)
+	}
+
+	// the host is not contacted any longer
+	response, err := get(t, doer, server.URL)
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+	assert.Nil(t, response)
+	assert.Equal(t, int32(config.FailureThreshold), server.requests.Load())
+
+	// until a trial request is allowed after the open duration, which fails again
+	advance(config.OpenDuration)
+	_, _ = get(t, doer, server.URL)
+	assert.Equal(t, int32(config.FailureThreshold+1), server.requests.Load())
+
+	_, err = get(t, doer, server.URL)
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+}
+
+func TestDoer_circuitBreakerCloses(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+		http.StatusServiceUnavailable, http.StatusOK)
+	doer, _, advance := newTestDoer(testConfig)
+
+	// the retries of the first request open the breaker
+	_, _ = get(t, doer, server.URL)
+	_, err := get(t, doer, server.URL)
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+
+	advance(testConfig.OpenDuration)
+	response, err := get(t, doer, server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = get(t, doer, server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestDoer_circuitBreakerPerHost(t *testing.T) {
+	failingServer := newStubServer(t, http.StatusServiceUnavailable)
+	server := newStubServer(t, http.StatusOK)
+	doer, _, _ := newTestDoer(testConfig)
+
+	_, _ = get(t, doer, failingServer.URL)
+	_, err := get(t, doer, failingServer.URL)
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+
+	response, err := get(t, doer, server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestDoer_circuitBreakerDisabled(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	config := testConfig
+	config.FailureThreshold = 0
+	doer, _, _ := newTestDoer(config)
+
+	for i := 0; i < 5; i++ {
+		_, err := get(t, doer, server.URL)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(15), server.requests.Load())
+}
+
+func TestDoer_cancelledRequestNotCounted(t *testing.T) {
+	server := newStubServer(t, http.StatusOK)
+	config := testConfig
+	config.FailureThreshold = 1
+	doer, _, _ := newTestDoer(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := doer.Do(request)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	response, err := get(t, doer, server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"PFleetManagement/infrastructure/resilience"
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// tracedDoer creates a client span for every request of another HttpRequestDoer
+type tracedDoer struct {
+	doer    resilience.HttpRequestDoer
+	service string
+}
+
+// InstrumentDoer wraps the given HttpRequestDoer of the client of the given downstream service (e.g. "car") so that
+// a client span is created for every request. If the doer retries requests, the span covers all attempts.
+func InstrumentDoer(service string, doer resilience.HttpRequestDoer) resilience.HttpRequestDoer {
+	return &tracedDoer{
+		doer:    doer,
+		service: service,
+	}
+}
+
+func (d *tracedDoer) Do(req *http.Request) (*http.Response, error) {
+	_, span := tracer().Start(req.Context(), d.service+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.PeerService(d.service),
+			semconv.HTTPMethod(req.Method),
+			semconv.HTTPURL(req.URL.String()),
+		))
+
+	response, err := d.doer.Do(req)
+	if err == nil {
+		span.SetAttributes(semconv.HTTPStatusCode(response.StatusCode))
+		if response.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+		}
+	}
+	finish(span, err)
+	return response, err
+}
+
+// InjectTraceContext adds the trace context of the given context to the headers of the given request (as W3C
+// traceparent header), so that the downstream service continues the trace. It matches the RequestEditorFn of the
+// generated clients.
+func InjectTraceContext(ctx context.Context, req *http.Request) error {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return nil
+}
@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/logic/model"
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// tracedFleetDB creates a span for every operation of another database.FleetDB
+type tracedFleetDB struct {
+	next database.FleetDB
+}
+
+// InstrumentFleetDB wraps the given database.FleetDB so that a span is created for every operation
+func InstrumentFleetDB(fleetDB database.FleetDB) database.FleetDB {
+	return &tracedFleetDB{
+		next: fleetDB,
+	}
+}
+
+// start creates the span of an operation of the given method with the given attributes (typically the fleet ID)
+func (d *tracedFleetDB) start(ctx context.Context, method string,
+	attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	return tracer().Start(ctx, "FleetDB."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMongoDB, semconv.DBOperation(method)),
+		trace.WithAttributes(attributes...))
+}
+
+func (d *tracedFleetDB) AddFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error) {
+	ctx, span := d.start(ctx, "AddFleet", fleetAttribute(fleet.FleetID))
+	result, err := d.next.AddFleet(ctx, fleet)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) UpdateFleet(ctx context.Context, fleetId model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
+
+	ctx, span := d.start(ctx, "UpdateFleet", fleetAttribute(fleetId))
+	result, err := d.next.UpdateFleet(ctx, fleetId, update)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetFleet(ctx context.Context, fleetId model.FleetID) (*model.Fleet, error) {
+	ctx, span := d.start(ctx, "GetFleet", fleetAttribute(fleetId))
+	result, err := d.next.GetFleet(ctx, fleetId)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	ctx, span := d.start(ctx, "ListFleets")
+	result, err := d.next.ListFleets(ctx)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetFleetSizes(ctx context.Context) (map[model.FleetID]int, error) {
+	ctx, span := d.start(ctx, "GetFleetSizes")
+	result, err := d.next.GetFleetSizes(ctx)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) DeleteFleet(ctx context.Context, fleetId model.FleetID) error {
+	ctx, span := d.start(ctx, "DeleteFleet", fleetAttribute(fleetId))
+	err := d.next.DeleteFleet(ctx, fleetId)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) AddCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	ctx, span := d.start(ctx, "AddCarToFleet", fleetAttribute(fleetId), vinAttribute(vin))
+	err := d.next.AddCarToFleet(ctx, fleetId, vin)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) AddCarsToFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) (map[model.Vin]error, error) {
+
+	ctx, span := d.start(ctx, "AddCarsToFleet", fleetAttribute(fleetId))
+	result, err := d.next.AddCarsToFleet(ctx, fleetId, vins)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) MoveCarToFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin,
+	targetFleetId model.FleetID) error {
+
+	ctx, span := d.start(ctx, "MoveCarToFleet", fleetAttribute(fleetId), targetFleetAttribute(targetFleetId), vinAttribute(vin))
+	err := d.next.MoveCarToFleet(ctx, fleetId, vin, targetFleetId)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) RemoveCarFromFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) error {
+	ctx, span := d.start(ctx, "RemoveCarFromFleet", fleetAttribute(fleetId), vinAttribute(vin))
+	err := d.next.RemoveCarFromFleet(ctx, fleetId, vin)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) RemoveCarsFromFleet(ctx context.Context, fleetId model.FleetID,
+	vins []model.Vin) ([]model.Vin, error) {
+
+	ctx, span := d.start(ctx, "RemoveCarsFromFleet", fleetAttribute(fleetId))
+	result, err := d.next.RemoveCarsFromFleet(ctx, fleetId, vins)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	ctx, span := d.start(ctx, "DecommissionCar", vinAttribute(vin))
+	err := d.next.DecommissionCar(ctx, vin, reason)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) GetCarsForFleet(ctx context.Context, fleetId model.FleetID,
+	asOf *time.Time) ([]model.Vin, error) {
+
+	ctx, span := d.start(ctx, "GetCarsForFleet", fleetAttribute(fleetId))
+	result, err := d.next.GetCarsForFleet(ctx, fleetId, asOf)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetCarsForFleetPage(ctx context.Context, fleetId model.FleetID, asOf *time.Time,
+	order database.VinOrder, offset, limit int) ([]model.Vin, error) {
+
+	ctx, span := d.start(ctx, "GetCarsForFleetPage", fleetAttribute(fleetId))
+	result, err := d.next.GetCarsForFleetPage(ctx, fleetId, asOf, order, offset, limit)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) IsCarInFleet(ctx context.Context, fleetId model.FleetID, vin model.Vin) (bool, error) {
+	ctx, span := d.start(ctx, "IsCarInFleet", fleetAttribute(fleetId), vinAttribute(vin))
+	result, err := d.next.IsCarInFleet(ctx, fleetId, vin)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetFleetHistory(ctx context.Context, fleetId model.FleetID, from, to *time.Time,
+	offset, limit int) ([]model.AuditEntry, error) {
+
+	ctx, span := d.start(ctx, "GetFleetHistory", fleetAttribute(fleetId))
+	result, err := d.next.GetFleetHistory(ctx, fleetId, from, to, offset, limit)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) SetRole(ctx context.Context, fleetId model.FleetID, subject model.Subject,
+	role model.Role) error {
+
+	ctx, span := d.start(ctx, "SetRole", fleetAttribute(fleetId))
+	err := d.next.SetRole(ctx, fleetId, subject, role)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) DeleteRole(ctx context.Context, fleetId model.FleetID, subject model.Subject) error {
+	ctx, span := d.start(ctx, "DeleteRole", fleetAttribute(fleetId))
+	err := d.next.DeleteRole(ctx, fleetId, subject)
+	finish(span, err)
+	return err
+}
+
+func (d *tracedFleetDB) GetRole(ctx context.Context, fleetId model.FleetID,
+	subject model.Subject) (*model.Role, error) {
+
+	ctx, span := d.start(ctx, "GetRole", fleetAttribute(fleetId))
+	result, err := d.next.GetRole(ctx, fleetId, subject)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetRoles(ctx context.Context, subject model.Subject) (map[model.FleetID]model.Role, error) {
+	ctx, span := d.start(ctx, "GetRoles")
+	result, err := d.next.GetRoles(ctx, subject)
+	finish(span, err)
+	return result, err
+}
+
+func (d *tracedFleetDB) GetMembers(ctx context.Context, fleetId model.FleetID) ([]model.Membership, error) {
+	ctx, span := d.start(ctx, "GetMembers", fleetAttribute(fleetId))
+	result, err := d.next.GetMembers(ctx, fleetId)
+	finish(span, err)
+	return result, err
+}
+
+// Ping is not traced, as it is not part of handling requests but used by the readiness probe
+func (d *tracedFleetDB) Ping(ctx context.Context) error {
+	return d.next.Ping(ctx)
+}
+
+func (d *tracedFleetDB) CleanUpDatabase() error {
+	return d.next.CleanUpDatabase()
+}
+
+func (d *tracedFleetDB) DropCollection(ctx context.Context) error {
+	return d.next.DropCollection(ctx)
+}
@@ -0,0 +1,192 @@
+package tracing
+
+import (
+	"PFleetManagement/logic/model"
+	"PFleetManagement/logic/operations"
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedOperations creates a span for every operation of another operations.IOperations
+type tracedOperations struct {
+	next operations.IOperations
+}
+
+// InstrumentOperations wraps the given operations.IOperations so that a span is created for every operation, which
+// is the parent of the spans of the database and the downstream services it uses
+func InstrumentOperations(next operations.IOperations) operations.IOperations {
+	return tracedOperations{
+		next: next,
+	}
+}
+
+// start creates the span of the operation with the given name and attributes (typically the fleet ID)
+func (o tracedOperations) start(ctx context.Context, name string,
+	attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	return tracer().Start(ctx, "operations."+name, trace.WithAttributes(attributes...))
+}
+
+func (o tracedOperations) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	ctx, span := o.start(ctx, "ListFleets")
+	result, err := o.next.ListFleets(ctx)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) CreateFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error) {
+	ctx, span := o.start(ctx, "CreateFleet", fleetAttribute(fleet.FleetID))
+	result, err := o.next.CreateFleet(ctx, fleet)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) GetFleet(ctx context.Context, fleetID model.FleetID) (*model.Fleet, error) {
+	ctx, span := o.start(ctx, "GetFleet", fleetAttribute(fleetID))
+	result, err := o.next.GetFleet(ctx, fleetID)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) UpdateFleet(ctx context.Context, fleetID model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
+
+	ctx, span := o.start(ctx, "UpdateFleet", fleetAttribute(fleetID))
+	result, err := o.next.UpdateFleet(ctx, fleetID, update)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) DeleteFleet(ctx context.Context, fleetID model.FleetID) error {
+	ctx, span := o.start(ctx, "DeleteFleet", fleetAttribute(fleetID))
+	err := o.next.DeleteFleet(ctx, fleetID)
+	finish(span, err)
+	return err
+}
+
+func (o tracedOperations) GetFleetHistory(ctx context.Context, fleetID model.FleetID,
+	query model.HistoryQuery) (*model.HistoryPage, error) {
+
+	ctx, span := o.start(ctx, "GetFleetHistory", fleetAttribute(fleetID))
+	result, err := o.next.GetFleetHistory(ctx, fleetID, query)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) GetCarsInFleet(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.CarPage, error) {
+
+	ctx, span := o.start(ctx, "GetCarsInFleet", fleetAttribute(fleetID))
+	result, err := o.next.GetCarsInFleet(ctx, fleetID, query)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) GetCarsInFleetTolerant(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.FleetOverview, error) {
+
+	ctx, span := o.start(ctx, "GetCarsInFleetTolerant", fleetAttribute(fleetID))
+	result, err := o.next.GetCarsInFleetTolerant(ctx, fleetID, query)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) RemoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) error {
+	ctx, span := o.start(ctx, "RemoveCar", fleetAttribute(fleetID), vinAttribute(vin))
+	err := o.next.RemoveCar(ctx, fleetID, vin)
+	finish(span, err)
+	return err
+}
+
+func (o tracedOperations) GetCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.Car, error) {
+	ctx, span := o.start(ctx, "GetCar", fleetAttribute(fleetID), vinAttribute(vin))
+	result, err := o.next.GetCar(ctx, fleetID, vin)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) AddCarToFleet(ctx context.Context, fleetID model.FleetID,
+	vin model.Vin) (*model.CarBase, error) {
+
+	ctx, span := o.start(ctx, "AddCarToFleet", fleetAttribute(fleetID), vinAttribute(vin))
+	result, err := o.next.AddCarToFleet(ctx, fleetID, vin)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) CreateCarInFleet(ctx context.Context, fleetID model.FleetID,
+	car model.Car) (*model.CarBase, error) {
+
+	ctx, span := o.start(ctx, "CreateCarInFleet", fleetAttribute(fleetID), vinAttribute(car.Vin))
+	result, err := o.next.CreateCarInFleet(ctx, fleetID, car)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) AddCarsToFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	ctx, span := o.start(ctx, "AddCarsToFleet", fleetAttribute(fleetID))
+	result, err := o.next.AddCarsToFleet(ctx, fleetID, vins)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) RemoveCarsFromFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	ctx, span := o.start(ctx, "RemoveCarsFromFleet", fleetAttribute(fleetID))
+	result, err := o.next.RemoveCarsFromFleet(ctx, fleetID, vins)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) MoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin,
+	targetFleetID model.FleetID) error {
+
+	ctx, span := o.start(ctx, "MoveCar", fleetAttribute(fleetID), vinAttribute(vin),
+		targetFleetAttribute(targetFleetID))
+	err := o.next.MoveCar(ctx, fleetID, vin, targetFleetID)
+	finish(span, err)
+	return err
+}
+
+func (o tracedOperations) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	ctx, span := o.start(ctx, "DecommissionCar", vinAttribute(vin))
+	err := o.next.DecommissionCar(ctx, vin, reason)
+	finish(span, err)
+	return err
+}
+
+func (o tracedOperations) ReconcileFleets(ctx context.Context,
+	dryRun bool) (*model.ReconciliationReport, error) {
+
+	ctx, span := o.start(ctx, "ReconcileFleets", attribute.Bool("dryRun", dryRun))
+	result, err := o.next.ReconcileFleets(ctx, dryRun)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) GetMembers(ctx context.Context, fleetID model.FleetID) ([]model.Membership, error) {
+	ctx, span := o.start(ctx, "GetMembers", fleetAttribute(fleetID))
+	result, err := o.next.GetMembers(ctx, fleetID)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) GrantRole(ctx context.Context, fleetID model.FleetID, subject model.Subject,
+	role model.Role) (*model.Membership, error) {
+
+	ctx, span := o.start(ctx, "GrantRole", fleetAttribute(fleetID))
+	result, err := o.next.GrantRole(ctx, fleetID, subject, role)
+	finish(span, err)
+	return result, err
+}
+
+func (o tracedOperations) RevokeRole(ctx context.Context, fleetID model.FleetID, subject model.Subject) error {
+	ctx, span := o.start(ctx, "RevokeRole", fleetAttribute(fleetID))
+	err := o.next.RevokeRole(ctx, fleetID, subject)
+	finish(span, err)
+	return err
+}
@@ -0,0 +1,157 @@
+// Package tracing instruments this service, its database and the clients of the downstream services with
+// OpenTelemetry spans and exports them.
+package tracing
+
+import (
+	"PFleetManagement/logic/model"
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// instrumentationName identifies the tracer of this service
+const instrumentationName = "PFleetManagement"
+
+// defaultServiceName is the service name of the exported spans unless overridden with OTEL_SERVICE_NAME
+const defaultServiceName = "fleet-management"
+
+// unmatchedRoute is the route of requests which do not match any route (echo leaves the path empty for them)
+const unmatchedRoute = "unmatched"
+
+// The exporters the spans can be sent to
+const (
+	// ExporterNone does not record spans, but the trace context of incoming requests is still propagated
+	ExporterNone = "none"
+
+	// ExporterStdout writes the spans to the standard output
+	ExporterStdout = "stdout"
+
+	// ExporterOTLP sends the spans to an OTLP collector over HTTP, configured by the standard OTEL_EXPORTER_OTLP_*
+	// environment variables
+	ExporterOTLP = "otlp"
+)
+
+// Setup installs the global tracer provider exporting spans to the given exporter (one of ExporterNone,
+// ExporterStdout and ExporterOTLP) and the W3C trace context propagator. The returned function flushes the pending
+// spans and stops the export.
+func Setup(ctx context.Context, exporterName string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{},
+		propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch exporterName {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporterName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence over the default service name
+	serviceResource, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(defaultServiceName)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// The keys of the attributes identifying the fleets and cars an operation refers to
+const (
+	fleetKey       = attribute.Key("fleet.id")
+	targetFleetKey = attribute.Key("fleet.target_id")
+	vinKey         = attribute.Key("car.vin")
+)
+
+func fleetAttribute(fleetID model.FleetID) attribute.KeyValue {
+	return fleetKey.String(fleetID)
+}
+
+func targetFleetAttribute(fleetID model.FleetID) attribute.KeyValue {
+	return targetFleetKey.String(fleetID)
+}
+
+func vinAttribute(vin model.Vin) attribute.KeyValue {
+	return vinKey.String(vin)
+}
+
+// tracer returns the tracer of the currently installed global tracer provider
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// finish marks the given span as failed if err is not nil and ends it
+func finish(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Middleware creates a server span for every request which continues the trace given by the traceparent header of
+// the request (if any) and passes it to the handlers with the context of the request. It has to precede all other
+// middlewares so that the requests rejected by them are traced as well.
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		request := ctx.Request()
+		parent := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+		// the route is used instead of the path to keep the number of span names bounded
+		route := ctx.Path()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		spanCtx, span := tracer().Start(parent, request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(request.Method),
+				semconv.HTTPRoute(route),
+				semconv.HTTPTarget(request.URL.RequestURI()),
+			))
+		defer span.End()
+		ctx.SetRequest(request.WithContext(spanCtx))
+
+		// errors are handled here (instead of being returned) as the status code is unknown before
+		err := next(ctx)
+		if err != nil {
+			ctx.Error(err)
+		}
+
+		status := ctx.Response().Status
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		return nil
+	}
+}
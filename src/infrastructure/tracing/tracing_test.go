@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"PFleetManagement/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// recordSpans installs a tracer provider which records all ended spans in the returned recorder
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+// attributeOf returns the value of the attribute of the given span with the given key
+func attributeOf(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSetup_unknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), "jaeger")
+	assert.NotNil(t, err)
+}
+
+func TestSetup_none(t *testing.T) {
+	shutdown, err := Setup(context.Background(), ExporterNone)
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	e := echo.New()
+	e.Use(Middleware)
+	var handlerSpan trace.SpanContext
+	e.GET("/fleets/:fleetID", func(ctx echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(ctx.Request().Context())
+		if ctx.Param("fleetID") == "unknown1" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return errors.New("database error")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/fleets/jJd9jb8I", nil)
+	request.Header.Set("traceparent", traceParent)
+	response := httptest.NewRecorder()
+	e.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fleets/unknown1", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	// the first request continues the given trace and fails
+	assert.Equal(t, "GET /fleets/:fleetID", spans[0].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(http.StatusInternalServerError), attributeOf(spans[0], "http.status_code").AsInt64())
+
+	// client errors are not errors of the service
+	assert.Equal(t, "GET /fleets/:fleetID", spans[1].Name())
+	assert.False(t, spans[1].Parent().IsValid())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, int64(http.StatusNotFound), attributeOf(spans[1], "http.status_code").AsInt64())
+	assert.Equal(t, spans[1].SpanContext(), handlerSpan)
+}
+
+func TestInstrumentDoer(t *testing.T) {
+	recorder := recordSpans(t)
+	var sentTraceParent string
+	doer := InstrumentDoer("car", doerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/cars/unavailable" {
+			return nil, fleetErrors.ErrServiceUnavailable
+		}
+		sentTraceParent = req.Header.Get("traceparent")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	request := httptest.NewRequest(http.MethodGet, "/cars/WVWAA71K08W201030", nil).WithContext(ctx)
+	assert.Nil(t, InjectTraceContext(ctx, request))
+	_, err := doer.Do(request)
+	assert.Nil(t, err)
+
+	request = httptest.NewRequest(http.MethodGet, "/cars/unavailable", nil).WithContext(ctx)
+	_, err = doer.Do(request)
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+	parent.End()
+
+	// the trace context is passed on to the downstream service
+	assert.Equal(t, "00-"+parent.SpanContext().TraceID().String()+"-"+parent.SpanContext().SpanID().String()+"-01",
+		sentTraceParent)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "car GET", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, int64(http.StatusOK), attributeOf(spans[0], "http.status_code").AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestInstrumentFleetDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := recordSpans(t)
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	fleetDB := InstrumentFleetDB(mockDB)
+
+	mockDB.EXPECT().GetFleet(gomock.Any(), "jJd9jb8I").Return(&model.Fleet{FleetID: "jJd9jb8I"}, nil)
+	mockDB.EXPECT().RemoveCarFromFleet(gomock.Any(), "jJd9jb8I", "WVWAA71K08W201030").
+		Return(fleetErrors.ErrCarNotInFleet)
+
+	fleet, err := fleetDB.GetFleet(context.Background(), "jJd9jb8I")
+	assert.Nil(t, err)
+	assert.Equal(t, "jJd9jb8I", fleet.FleetID)
+
+	err = fleetDB.RemoveCarFromFleet(context.Background(), "jJd9jb8I", "WVWAA71K08W201030")
+	assert.ErrorIs(t, err, fleetErrors.ErrCarNotInFleet)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "FleetDB.GetFleet", spans[0].Name())
+	assert.Equal(t, "jJd9jb8I", attributeOf(spans[0], fleetKey).AsString())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "FleetDB.RemoveCarFromFleet", spans[1].Name())
+	assert.Equal(t, "WVWAA71K08W201030", attributeOf(spans[1], vinKey).AsString())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestInstrumentOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := recordSpans(t)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	operations := InstrumentOperations(mockOperations)
+
+	// the span of the operation is passed on with the context
+	var operationSpan trace.SpanContext
+	mockOperations.EXPECT().MoveCar(gomock.Any(), "jJd9jb8I", "WVWAA71K08W201030", "xk48jpgz").
+		DoAndReturn(func(ctx context.Context, _, _, _ string) error {
+			operationSpan = trace.SpanContextFromContext(ctx)
+			return fleetErrors.ErrFleetNotFound
+		})
+
+	err := operations.MoveCar(context.Background(), "jJd9jb8I", "WVWAA71K08W201030", "xk48jpgz")
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "operations.MoveCar", spans[0].Name())
+	assert.Equal(t, operationSpan, spans[0].SpanContext())
+	assert.Equal(t, "jJd9jb8I", attributeOf(spans[0], fleetKey).AsString())
+	assert.Equal(t, "xk48jpgz", attributeOf(spans[0], targetFleetKey).AsString())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
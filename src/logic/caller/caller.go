@@ -0,0 +1,55 @@
+// Package caller carries information about the origin of a request through the layers of this service, e.g. to
+// record who changed a fleet.
+package caller
+
+import (
+	"PFleetManagement/logic/model"
+	"context"
+)
+
+// Info describes the origin of a request
+type Info struct {
+	// Actor identifies who sent the request. It is empty if the caller is unknown.
+	Actor string
+
+	// RequestID identifies the request. It is empty if the request has no ID.
+	RequestID string
+
+	// Fleets are the IDs of the fleets the caller manages according to their token
+	Fleets []model.FleetID
+
+	// Admin shows that the caller may access all fleets according to their token
+	Admin bool
+
+	// Unrestricted shows that the caller may access all fleets without a token, which is only the case if requests
+	// are deliberately not authenticated (e.g. in the local setup mode). Callers without a token are not permitted
+	// anything otherwise.
+	Unrestricted bool
+}
+
+// Manages reports whether the caller manages the fleet with the given ID according to their token (or is not
+// restricted to specific fleets)
+func (i Info) Manages(fleetID model.FleetID) bool {
+	if i.Unrestricted {
+		return true
+	}
+	for _, managed := range i.Fleets {
+		if managed == fleetID {
+			return true
+		}
+	}
+	return false
+}
+
+type infoKey struct{}
+
+// WithInfo attaches the given Info to the context
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the Info attached to the context or an empty Info if there is none
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
@@ -15,6 +15,15 @@ var (
 	// ErrCarAlreadyInFleet shows that a car with a given VIN is already assigned to a given fleet
 	ErrCarAlreadyInFleet = errors.New("car already in fleet")
 
+	// ErrCarAlreadyExists shows that a car cannot be created as there already is a car with the given VIN
+	ErrCarAlreadyExists = errors.New("car already exists")
+
+	// ErrCarAssignedToOtherFleet shows that a car cannot be assigned to a fleet as it is assigned to another one
+	ErrCarAssignedToOtherFleet = errors.New("car assigned to other fleet")
+
+	// ErrCarRented shows that a car cannot be decommissioned as it has an active or upcoming rental
+	ErrCarRented = errors.New("car has an active or upcoming rental")
+
 	// ErrFleetAlreadyExists shows that there already is a fleet with a given fleet ID
 	ErrFleetAlreadyExists = errors.New("fleet already exists")
 
@@ -24,9 +33,24 @@ var (
 	// ErrRentalManagementAssertion occurs when an unexpected response is received from the RentalManagement microservice
 	ErrRentalManagementAssertion = errors.New("unexpected response from rental management service")
 
+	// ErrServiceUnavailable occurs when a downstream service is considered unavailable, so that no requests are sent
+	ErrServiceUnavailable = errors.New("downstream service unavailable")
+
 	// ErrInvalidVin shows that the format of a VIN is invalid
 	ErrInvalidVin = errors.New("invalid vin")
 
 	// ErrInvalidFleetId shows that the format of a fleet ID is invalid
 	ErrInvalidFleetId = errors.New("invalid fleet id")
+
+	// ErrInvalidCursor shows that a cursor for paging through a list cannot be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrUnauthenticated shows that a request does not carry a valid bearer token
+	ErrUnauthenticated = errors.New("not authenticated")
+
+	// ErrForbidden shows that the role of the authenticated caller in a fleet the request refers to is insufficient
+	ErrForbidden = errors.New("insufficient role in fleet")
+
+	// ErrMembershipNotFound shows that a user has no role in a given fleet
+	ErrMembershipNotFound = errors.New("no such member")
 )
@@ -6,6 +6,41 @@ import (
 	"time"
 )
 
+// Defines values for AuditAction.
+const (
+	AuditCarAdded          AuditAction = "carAdded"
+	AuditCarDecommissioned AuditAction = "carDecommissioned"
+	AuditCarMovedIn        AuditAction = "carMovedIn"
+	AuditCarMovedOut       AuditAction = "carMovedOut"
+	AuditCarRemoved        AuditAction = "carRemoved"
+	AuditFleetCreated      AuditAction = "fleetCreated"
+	AuditFleetDeleted      AuditAction = "fleetDeleted"
+	AuditFleetUpdated      AuditAction = "fleetUpdated"
+)
+
+// Defines values for BatchOutcome.
+const (
+	BatchAdded                BatchOutcome = "added"
+	BatchAlreadyInFleet       BatchOutcome = "alreadyInFleet"
+	BatchAssignedToOtherFleet BatchOutcome = "assignedToOtherFleet"
+	BatchInvalid              BatchOutcome = "invalid"
+	BatchNotFound             BatchOutcome = "notFound"
+	BatchNotInFleet           BatchOutcome = "notInFleet"
+	BatchRemoved              BatchOutcome = "removed"
+)
+
+// Defines values for CarSortParam.
+const (
+	CarSortBrand              CarSortParam = "brand"
+	CarSortBrandDesc          CarSortParam = "-brand"
+	CarSortModel              CarSortParam = "model"
+	CarSortModelDesc          CarSortParam = "-model"
+	CarSortProductionDate     CarSortParam = "productionDate"
+	CarSortProductionDateDesc CarSortParam = "-productionDate"
+	CarSortVin                CarSortParam = "vin"
+	CarSortVinDesc            CarSortParam = "-vin"
+)
+
 // Defines values for DynamicDataEngineState.
 const (
 	OFF DynamicDataEngineState = "OFF"
@@ -18,6 +53,13 @@ const (
 	UNLOCKED LockState = "UNLOCKED"
 )
 
+// Defines values for Role.
+const (
+	RoleAdmin   Role = "admin"
+	RoleManager Role = "manager"
+	RoleViewer  Role = "viewer"
+)
+
 // Defines values for TechnicalSpecificationFuel.
 const (
 	DIESEL       TechnicalSpecificationFuel = "DIESEL"
@@ -33,6 +75,54 @@ const (
 	MANUAL    TechnicalSpecificationTransmission = "MANUAL"
 )
 
+// AuditAction The kind of change of a fleet
+type AuditAction string
+
+// AuditEntry A recorded change of a fleet or of the cars assigned to it
+type AuditEntry struct {
+	// Action The kind of change of a fleet
+	Action AuditAction `json:"action"`
+
+	// Actor Identifies who made the change, if known
+	Actor *string `json:"actor,omitempty"`
+
+	// FleetID Unique identification of a car fleet
+	FleetID FleetID `json:"fleetID"`
+
+	// OtherFleetID The fleet a car was moved from or to
+	OtherFleetID *FleetID `json:"otherFleetID,omitempty"`
+
+	// Reason Why a car was decommissioned
+	Reason *string `json:"reason,omitempty"`
+
+	// RequestID Identifies the request which made the change, if known
+	RequestID *string `json:"requestID,omitempty"`
+
+	// Timestamp The point in time of the change
+	Timestamp time.Time `json:"timestamp"`
+
+	// Vin A Vehicle Identification Number (VIN) which uniquely identifies a Vehicle
+	Vin *Vin `json:"vin,omitempty"`
+}
+
+// BatchCarResult The outcome of a batch operation for a single car
+type BatchCarResult struct {
+	// Outcome The outcome for a single car of a batch operation
+	Outcome BatchOutcome `json:"outcome"`
+
+	// Vin The VIN as given in the request
+	Vin string `json:"vin"`
+}
+
+// BatchOutcome The outcome for a single car of a batch operation
+type BatchOutcome string
+
+// BatchResult The outcome of a batch operation on the cars of a fleet
+type BatchResult struct {
+	// Results The outcome for every distinct VIN of the request, in the order of the request
+	Results []BatchCarResult `json:"results"`
+}
+
 // Car defines model for car.
 type Car struct {
 	// Brand Data that specifies the brand name of the Vehicle manufacturer
@@ -70,6 +160,33 @@ type CarBase struct {
 	Vin Vin `json:"vin"`
 }
 
+// CarError An error which occurred while resolving a single car
+type CarError struct {
+	// Message A message that describes why the car could not be resolved
+	Message string `json:"message"`
+
+	// Vin A Vehicle Identification Number (VIN) which uniquely identifies a Vehicle
+	Vin Vin `json:"vin"`
+}
+
+// DanglingCar A car which is assigned to a fleet but not known to the Car service
+type DanglingCar struct {
+	// FleetID Unique identification of a car fleet
+	FleetID FleetID `json:"fleetId"`
+
+	// Pruned Whether the car has been removed from the fleet
+	Pruned bool `json:"pruned"`
+
+	// Vin A Vehicle Identification Number (VIN) which uniquely identifies a Vehicle
+	Vin Vin `json:"vin"`
+}
+
+// Decommissioning Why a car is decommissioned
+type Decommissioning struct {
+	// Reason Why the car is decommissioned, e.g. "total loss"
+	Reason string `json:"reason"`
+}
+
 // DynamicData Data that changes during a car's operation
 type DynamicData struct {
 	// DoorsLockState Data that specifies whether an object is locked or unlocked
@@ -89,9 +206,178 @@ type DynamicData struct {
 // DynamicDataEngineState defines model for DynamicData.EngineState.
 type DynamicDataEngineState string
 
+// Fleet A car fleet
+type Fleet struct {
+	// CreatedAt The point in time the fleet was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Description A free-form description of the fleet
+	Description string `json:"description,omitempty"`
+
+	// FleetID Unique identification of a car fleet
+	FleetID FleetID `json:"fleetID"`
+
+	// Labels Free-form key-value pairs attached to the fleet
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Name A human-readable name of the fleet
+	Name string `json:"name,omitempty"`
+
+	// Owner The organisation owning the fleet
+	Owner string `json:"owner,omitempty"`
+
+	// UpdatedAt The point in time the descriptive data of the fleet was last changed
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// FleetCreation The data required to create a new car fleet
+type FleetCreation struct {
+	// Description A free-form description of the fleet
+	Description string `json:"description,omitempty"`
+
+	// FleetID Unique identification of a car fleet
+	FleetID FleetID `json:"fleetID"`
+
+	// Labels Free-form key-value pairs attached to the fleet
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Name A human-readable name of the fleet
+	Name string `json:"name,omitempty"`
+
+	// Owner The organisation owning the fleet
+	Owner string `json:"owner,omitempty"`
+}
+
+// FleetOverview Overview of the cars assigned to a fleet tolerating errors of individual cars
+type FleetOverview struct {
+	// Cars The cars assigned to the fleet which could be resolved
+	Cars []CarBase `json:"cars"`
+
+	// Errors An error for each car assigned to the fleet which could not be resolved
+	Errors []CarError `json:"errors"`
+
+	// NextCursor An opaque position in a list of cars, which is returned if there are further cars after the current page
+	NextCursor *Cursor `json:"nextCursor,omitempty"`
+}
+
+// CarPage A page of the cars assigned to a fleet
+type CarPage struct {
+	// Cars The cars of the page
+	Cars []CarBase
+
+	// NextCursor The position of the next page, nil if there are no further cars
+	NextCursor *Cursor
+}
+
+// HistoryPage A page of the history of a fleet
+type HistoryPage struct {
+	// Entries The entries of the page in chronological order
+	Entries []AuditEntry
+
+	// NextCursor The position of the next page, nil if there are no further entries
+	NextCursor *Cursor
+}
+
+// HistoryQuery Selects and pages the history of a fleet. Nil properties are ignored.
+type HistoryQuery struct {
+	// Cursor The position to continue at as returned by the previous page
+	Cursor *Cursor
+
+	// From Only select entries recorded at or after the given point in time
+	From *time.Time
+
+	// Limit The maximum number of entries to select
+	Limit *int
+
+	// To Only select entries recorded before the given point in time
+	To *time.Time
+}
+
+// CarQuery Selects, orders and pages the cars assigned to a fleet. Nil properties are ignored.
+type CarQuery struct {
+	// AsOf Select the cars assigned at the given point in time instead of the current ones
+	AsOf *time.Time
+
+	// Brand Only select cars of the given brand
+	Brand *string
+
+	// Cursor The position to continue at as returned by the previous page
+	Cursor *Cursor
+
+	// Limit The maximum number of cars to select
+	Limit *int
+
+	// Model Only select cars of the given model
+	Model *string
+
+	// ProductionDateFrom Only select cars produced on or after the given date
+	ProductionDateFrom *openapiTypes.Date
+
+	// ProductionDateTo Only select cars produced on or before the given date
+	ProductionDateTo *openapiTypes.Date
+
+	// Sort The property to sort the cars by
+	Sort *CarSortParam
+}
+
+// Cursor An opaque position in a list of cars
+type Cursor = string
+
+// FleetUpdate Changes to the descriptive data of a car fleet. Only the given (non-nil) properties are replaced.
+type FleetUpdate struct {
+	// Description A free-form description of the fleet
+	Description *string `json:"description,omitempty"`
+
+	// Labels Free-form key-value pairs attached to the fleet
+	Labels *map[string]string `json:"labels,omitempty"`
+
+	// Name A human-readable name of the fleet
+	Name *string `json:"name,omitempty"`
+
+	// Owner The organisation owning the fleet
+	Owner *string `json:"owner,omitempty"`
+}
+
 // FleetID Unique identification of a car fleet
 type FleetID = string
 
+// Membership The role of a user in a fleet
+type Membership struct {
+	// Role The permissions of the user in the fleet
+	Role Role `json:"role"`
+
+	// Subject The user (as identified by the subject of their bearer tokens)
+	Subject Subject `json:"subject"`
+}
+
+// Role The permissions of a user in a fleet. Viewers can read the fleet and its cars, managers can change them as well
+// and admins can additionally grant and revoke the roles of other users.
+type Role string
+
+// ReconciliationReport The outcome of checking the cars assigned to all fleets against the Car service
+type ReconciliationReport struct {
+	// CheckedCars The number of cars assigned to the checked fleets
+	CheckedCars int `json:"checkedCars"`
+
+	// CheckedFleets The number of checked fleets
+	CheckedFleets int `json:"checkedFleets"`
+
+	// DanglingCars The cars which are not known to the Car service, ordered by fleet
+	DanglingCars []DanglingCar `json:"danglingCars"`
+
+	// DryRun Whether the dangling cars have only been reported without removing them from their fleets
+	DryRun bool `json:"dryRun"`
+}
+
+// RoleGrant The role to grant to a user in a fleet
+type RoleGrant struct {
+	// Role The permissions of the user in the fleet
+	Role Role `json:"role"`
+}
+
+// Subject Identification of a user as given by the subject of their bearer tokens
+type Subject = string
+
 // LockState Data that specifies whether an object is locked or unlocked
 type LockState string
 
@@ -189,12 +475,120 @@ type TechnicalSpecificationTransmission string
 // Vin A Vehicle Identification Number (VIN) which uniquely identifies a Vehicle
 type Vin = string
 
+// VinList A list of cars (by their VINs) to process at once
+type VinList struct {
+	// Vins The VINs of the cars. VINs with an invalid format do not fail the request but are reported individually.
+	Vins []string `json:"vins"`
+}
+
 // FleetIDParam Unique identification of a car fleet
 type FleetIDParam = FleetID
 
+// TolerateErrorsParam Whether cars which cannot be resolved are reported individually instead of failing the whole request
+type TolerateErrorsParam = bool
+
+// LimitParam The maximum number of cars to return
+type LimitParam = int
+
+// CursorParam The position to continue at as returned by the previous page
+type CursorParam = Cursor
+
+// CarSortParam The property to sort the cars by, in descending order if prefixed with a minus
+type CarSortParam string
+
+// GetCarsInFleetParams defines parameters for GetCarsInFleet.
+type GetCarsInFleetParams struct {
+	// TolerateErrors Whether cars which cannot be resolved are reported individually instead of failing the whole request
+	TolerateErrors *TolerateErrorsParam `form:"tolerateErrors,omitempty" json:"tolerateErrors,omitempty"`
+
+	// Limit The maximum number of cars to return. If not given, all (remaining) cars are returned.
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The position to continue at as returned by the previous page. If not given, the first page is returned.
+	Cursor *CursorParam `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort The property to sort the cars by, in descending order if prefixed with a minus
+	Sort *CarSortParam `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Brand Only return cars of the given brand
+	Brand *string `form:"brand,omitempty" json:"brand,omitempty"`
+
+	// Model Only return cars of the given model
+	Model *string `form:"model,omitempty" json:"model,omitempty"`
+
+	// ProductionDateFrom Only return cars produced on or after the given date
+	ProductionDateFrom *openapiTypes.Date `form:"productionDateFrom,omitempty" json:"productionDateFrom,omitempty"`
+
+	// ProductionDateTo Only return cars produced on or before the given date
+	ProductionDateTo *openapiTypes.Date `form:"productionDateTo,omitempty" json:"productionDateTo,omitempty"`
+
+	// AsOf Return the cars which were assigned to the fleet at the given point in time instead of the current ones
+	AsOf *time.Time `form:"asOf,omitempty" json:"asOf,omitempty"`
+}
+
+// HistoryLimitParam The maximum number of history entries to return
+type HistoryLimitParam = int
+
+// GetFleetHistoryParams defines parameters for GetFleetHistory.
+type GetFleetHistoryParams struct {
+	// From Only return entries recorded at or after the given point in time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only return entries recorded before the given point in time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Limit The maximum number of history entries to return
+	Limit *HistoryLimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The position to continue at as returned by the previous page. If not given, the first page is returned.
+	Cursor *CursorParam `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// TargetFleetIDParam Identification number of the fleet the car is moved to
+type TargetFleetIDParam = FleetID
+
+// MoveCarParams defines parameters for MoveCar.
+type MoveCarParams struct {
+	// To Identification number of the fleet the car is moved to
+	To TargetFleetIDParam `form:"to" json:"to"`
+}
+
+// DryRunParam Whether inconsistencies are only reported without repairing them
+type DryRunParam = bool
+
+// ReconcileFleetsParams defines parameters for ReconcileFleets.
+type ReconcileFleetsParams struct {
+	// DryRun Whether inconsistencies are only reported without repairing them. Defaults to true.
+	DryRun *DryRunParam `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+}
+
+// CreateFleetJSONRequestBody defines body for CreateFleet for application/json ContentType.
+type CreateFleetJSONRequestBody = FleetCreation
+
+// UpdateFleetJSONRequestBody defines body for UpdateFleet for application/json ContentType.
+type UpdateFleetJSONRequestBody = FleetUpdate
+
+// CreateCarInFleetJSONRequestBody defines body for CreateCarInFleet for application/json ContentType.
+type CreateCarInFleetJSONRequestBody = Car
+
+// BatchAddCarsJSONRequestBody defines body for BatchAddCars for application/json ContentType.
+type BatchAddCarsJSONRequestBody = VinList
+
+// BatchRemoveCarsJSONRequestBody defines body for BatchRemoveCars for application/json ContentType.
+type BatchRemoveCarsJSONRequestBody = VinList
+
+// DecommissionCarJSONRequestBody defines body for DecommissionCar for application/json ContentType.
+type DecommissionCarJSONRequestBody = Decommissioning
+
+// GrantRoleJSONRequestBody defines body for GrantRole for application/json ContentType.
+type GrantRoleJSONRequestBody = RoleGrant
+
 // VinParam A Vehicle Identification Number (VIN) which uniquely identifies a Vehicle
 type VinParam = Vin
 
+// SubjectParam Identification of a user as given by the subject of their bearer tokens
+type SubjectParam = Subject
+
 // Rental defines a model for rentals.
 type Rental struct {
 	// Active Describes whether this rental is active
@@ -0,0 +1,168 @@
+package operations
+
+import (
+	"PFleetManagement/infrastructure/dcar"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"net/http"
+)
+
+func (o operations) AddCarsToFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	// --- database interaction ---
+	// fail early for unknown fleets instead of querying the Car service for all cars first
+	if _, err := o.database.GetFleet(ctx, fleetID); err != nil {
+		return nil, err
+	}
+
+	vins = distinctVins(vins)
+	outcomes := make(map[model.Vin]model.BatchOutcome, len(vins))
+	validVins := o.selectValidVins(vins, outcomes)
+
+	// --- Car service interaction ---
+	// check for the cars first to prevent VINs which no cars are known for to be stored in the database
+	exists, err := o.checkCarsExist(ctx, validVins)
+	if err != nil {
+		return nil, err
+	}
+
+	existingVins := make([]model.Vin, 0, len(validVins))
+	for index, vin := range validVins {
+		if !exists[index] {
+			outcomes[vin] = model.BatchNotFound
+			continue
+		}
+		existingVins = append(existingVins, vin)
+	}
+
+	// --- database interaction ---
+	failures, err := o.database.AddCarsToFleet(ctx, fleetID, existingVins)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vin := range existingVins {
+		switch failure := failures[vin]; {
+		case failure == nil:
+			outcomes[vin] = model.BatchAdded
+		case errors.Is(failure, fleetErrors.ErrCarAlreadyInFleet):
+			outcomes[vin] = model.BatchAlreadyInFleet
+		case errors.Is(failure, fleetErrors.ErrCarAssignedToOtherFleet):
+			outcomes[vin] = model.BatchAssignedToOtherFleet
+		default:
+			return nil, failure
+		}
+	}
+
+	return toBatchResult(vins, outcomes), nil
+}
+
+func (o operations) RemoveCarsFromFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	vins = distinctVins(vins)
+	outcomes := make(map[model.Vin]model.BatchOutcome, len(vins))
+	validVins := o.selectValidVins(vins, outcomes)
+
+	// --- database interaction ---
+	removedVins, err := o.database.RemoveCarsFromFleet(ctx, fleetID, validVins)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vin := range validVins {
+		outcomes[vin] = model.BatchNotInFleet
+	}
+	invalidator, caching := o.carClient.(dcar.CacheInvalidator)
+	for _, vin := range removedVins {
+		outcomes[vin] = model.BatchRemoved
+
+		// the data of the car is not required any longer if the Car service client caches it
+		if caching {
+			invalidator.Invalidate(vin)
+		}
+	}
+
+	return toBatchResult(vins, outcomes), nil
+}
+
+// checkCarsExist queries the Car service for the cars with the given VINs and reports for every VIN (in the same
+// order) whether the car exists. At most carRequestConcurrency requests are performed at the same time.
+// The whole operation fails if any request fails for another reason than an unknown car.
+func (o operations) checkCarsExist(ctx context.Context, vins []model.Vin) ([]bool, error) {
+	exists := make([]bool, len(vins))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(o.carRequestConcurrency)
+
+	// (only the existence is checked, so cached cars with outdated dynamic data are fine)
+	staticCtx := dcar.WithStaticDataSufficient(groupCtx)
+	for index, vin := range vins {
+		index, vin := index, vin
+		group.Go(func() error {
+			carResponse, err := o.carClient.GetCarWithResponse(staticCtx, vin)
+			if err != nil {
+				return err
+			}
+
+			switch statusCode := carResponse.StatusCode(); {
+			case carResponse.JSON200 != nil:
+				// remark: every goroutine writes to its own index, so no synchronization is required
+				exists[index] = true
+			case statusCode != http.StatusNotFound:
+				return fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+			}
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return exists, nil
+}
+
+// distinctVins removes repeated VINs, keeping the first occurrence of each VIN
+func distinctVins(vins []model.Vin) []model.Vin {
+	seen := make(map[model.Vin]bool, len(vins))
+	distinct := make([]model.Vin, 0, len(vins))
+	for _, vin := range vins {
+		if !seen[vin] {
+			seen[vin] = true
+			distinct = append(distinct, vin)
+		}
+	}
+	return distinct
+}
+
+// selectValidVins returns the VINs which have a valid format (see WithVinPattern) and records the outcome
+// model.BatchInvalid for the others
+func (o operations) selectValidVins(vins []model.Vin, outcomes map[model.Vin]model.BatchOutcome) []model.Vin {
+	// always non-nil, as the database cannot match against a missing list
+	valid := make([]model.Vin, 0, len(vins))
+	for _, vin := range vins {
+		if o.vinPattern != nil && !o.vinPattern.MatchString(vin) {
+			outcomes[vin] = model.BatchInvalid
+			continue
+		}
+		valid = append(valid, vin)
+	}
+	return valid
+}
+
+// toBatchResult collects the outcomes of the given VINs in the order of the VINs
+func toBatchResult(vins []model.Vin, outcomes map[model.Vin]model.BatchOutcome) *model.BatchResult {
+	results := make([]model.BatchCarResult, len(vins))
+	for index, vin := range vins {
+		results[index] = model.BatchCarResult{
+			Vin:     vin,
+			Outcome: outcomes[vin],
+		}
+	}
+	return &model.BatchResult{Results: results}
+}
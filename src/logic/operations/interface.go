@@ -12,8 +12,31 @@ import (
 // All blocking operations use the given context.
 // Returned errors are either from logic/errors or internal errors from library calls.
 type IOperations interface {
-	// GetCarsInFleet Get an overview of all cars assigned to the given fleet
-	GetCarsInFleet(ctx context.Context, fleetID model.FleetID) ([]model.CarBase, error)
+	// ListFleets Get all fleets
+	ListFleets(ctx context.Context) ([]model.Fleet, error)
+
+	// CreateFleet Create a new empty fleet with the given fleet ID and descriptive data
+	CreateFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error)
+
+	// GetFleet Get the fleet with the given fleet ID
+	GetFleet(ctx context.Context, fleetID model.FleetID) (*model.Fleet, error)
+
+	// UpdateFleet Change the descriptive data of the given fleet
+	UpdateFleet(ctx context.Context, fleetID model.FleetID, update model.FleetUpdate) (*model.Fleet, error)
+
+	// DeleteFleet Delete the given fleet including all of its car assignments
+	DeleteFleet(ctx context.Context, fleetID model.FleetID) error
+
+	// GetFleetHistory Get the recorded changes of the given fleet selected by the query in chronological order
+	GetFleetHistory(ctx context.Context, fleetID model.FleetID, query model.HistoryQuery) (*model.HistoryPage, error)
+
+	// GetCarsInFleet Get an overview of the cars assigned to the given fleet which are selected by the query
+	GetCarsInFleet(ctx context.Context, fleetID model.FleetID, query model.CarQuery) (*model.CarPage, error)
+
+	// GetCarsInFleetTolerant Get an overview of the cars assigned to the given fleet which are selected by the query.
+	// Cars which cannot be resolved do not fail the operation but are reported individually.
+	GetCarsInFleetTolerant(ctx context.Context, fleetID model.FleetID,
+		query model.CarQuery) (*model.FleetOverview, error)
 
 	// RemoveCar Remove the given car from the given fleet
 	RemoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) error
@@ -21,6 +44,38 @@ type IOperations interface {
 	// GetCar Get data and status of the given car assigned to the given fleet
 	GetCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.Car, error)
 
-	// AddCarToFleet Add (assign) the given car to the given fleet
+	// AddCarToFleet Add (assign) the given car to the given fleet. Fails if the car is assigned to another fleet.
 	AddCarToFleet(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.CarBase, error)
+
+	// CreateCarInFleet Create the given car in the Car service and add (assign) it to the given fleet. If the car
+	// cannot be assigned, it is deleted again.
+	CreateCarInFleet(ctx context.Context, fleetID model.FleetID, car model.Car) (*model.CarBase, error)
+
+	// AddCarsToFleet Add (assign) the given cars to the given fleet at once, reporting the outcome for every car
+	AddCarsToFleet(ctx context.Context, fleetID model.FleetID, vins []model.Vin) (*model.BatchResult, error)
+
+	// RemoveCarsFromFleet Remove the given cars from the given fleet at once, reporting the outcome for every car
+	RemoveCarsFromFleet(ctx context.Context, fleetID model.FleetID, vins []model.Vin) (*model.BatchResult, error)
+
+	// MoveCar Move the given car from the given fleet to the target fleet without being unassigned in between
+	MoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin, targetFleetID model.FleetID) error
+
+	// DecommissionCar Retire the given car: remove it from every fleet, delete it from the Car service and record
+	// the given reason. Fails if the car has an active or upcoming rental. Succeeds for cars which have already been
+	// deleted in the Car service, so that it can be retried.
+	DecommissionCar(ctx context.Context, vin model.Vin, reason string) error
+
+	// ReconcileFleets Check the cars assigned to all fleets against the Car service and report those which are not
+	// known to it. Unless dryRun is set, they are removed from their fleets.
+	ReconcileFleets(ctx context.Context, dryRun bool) (*model.ReconciliationReport, error)
+
+	// GetMembers Get the roles of all users in the given fleet
+	GetMembers(ctx context.Context, fleetID model.FleetID) ([]model.Membership, error)
+
+	// GrantRole Grant the given role in the given fleet to the given user, replacing their previous role
+	GrantRole(ctx context.Context, fleetID model.FleetID, subject model.Subject,
+		role model.Role) (*model.Membership, error)
+
+	// RevokeRole Revoke the role of the given user in the given fleet
+	RevokeRole(ctx context.Context, fleetID model.FleetID, subject model.Subject) error
 }
@@ -0,0 +1,27 @@
+package operations
+
+import (
+	"PFleetManagement/logic/model"
+	"context"
+)
+
+func (o operations) GetMembers(ctx context.Context, fleetID model.FleetID) ([]model.Membership, error) {
+	return o.database.GetMembers(ctx, fleetID)
+}
+
+func (o operations) GrantRole(ctx context.Context, fleetID model.FleetID, subject model.Subject,
+	role model.Role) (*model.Membership, error) {
+
+	if err := o.database.SetRole(ctx, fleetID, subject, role); err != nil {
+		return nil, err
+	}
+
+	return &model.Membership{
+		Role:    role,
+		Subject: subject,
+	}, nil
+}
+
+func (o operations) RevokeRole(ctx context.Context, fleetID model.FleetID, subject model.Subject) error {
+	return o.database.DeleteRole(ctx, fleetID, subject)
+}
@@ -4,17 +4,54 @@ import (
 	"PFleetManagement/infrastructure/database"
 	"PFleetManagement/infrastructure/dcar"
 	rentalManagement "PFleetManagement/infrastructure/rentalmanagement"
+	"PFleetManagement/infrastructure/resilience"
 	"PFleetManagement/logic/fleetErrors"
 	"PFleetManagement/logic/model"
 	"context"
+	"errors"
 	"fmt"
+	"golang.org/x/sync/errgroup"
+	"log"
 	"net/http"
+	"regexp"
 )
 
+// carUnavailableMessage is reported for cars which could not be resolved due to an error other than an unexpected
+// response of the Car service
+const carUnavailableMessage = "car could not be retrieved from domain service"
+
+// defaultHistoryLimit is the number of history entries which are returned if the query does not limit them
+const defaultHistoryLimit = 100
+
 type operations struct {
 	database               database.FleetDB
 	carClient              dcar.ClientWithResponsesInterface
 	rentalManagementClient rentalManagement.ClientWithResponsesInterface
+	carRequestConcurrency  int
+	vinPattern             *regexp.Regexp
+}
+
+// Option allows setting optional parameters of the operations during construction
+type Option func(*operations)
+
+// WithCarRequestConcurrency sets the maximum number of requests to the Car service which are performed
+// concurrently when resolving multiple cars at once (e.g. for the fleet overview).
+// Values smaller than one are ignored.
+func WithCarRequestConcurrency(concurrency int) Option {
+	return func(o *operations) {
+		if concurrency > 0 {
+			o.carRequestConcurrency = concurrency
+		}
+	}
+}
+
+// WithVinPattern sets the format of a VIN. The VINs of batch operations which do not match it are reported as
+// invalid instead of being processed, as they are not validated with the request (unlike single VINs).
+// Without a pattern, the format of VINs is not checked.
+func WithVinPattern(pattern *regexp.Regexp) Option {
+	return func(o *operations) {
+		o.vinPattern = pattern
+	}
 }
 
 // NewOperations creates an implementation of IOperations from its dependencies.
@@ -22,57 +59,289 @@ type operations struct {
 // The database.FleetDB is queried for/updated with the fleet-car assignment.
 //
 // The dcar.ClientWithResponsesInterface is queried for resolving VINs to full car data.
+// If not configured otherwise with WithCarRequestConcurrency, multiple cars are requested sequentially.
+// If configured with WithVinPattern, the VINs of batch operations are checked against the given format.
 func NewOperations(fleetDB database.FleetDB, carClient dcar.ClientWithResponsesInterface,
-	rentalManagementClient rentalManagement.ClientWithResponsesInterface) IOperations {
+	rentalManagementClient rentalManagement.ClientWithResponsesInterface, opts ...Option) IOperations {
 
-	return operations{
+	o := operations{
 		database:               fleetDB,
 		carClient:              carClient,
 		rentalManagementClient: rentalManagementClient,
+		carRequestConcurrency:  1,
+	}
+	for _, opt := range opts {
+		opt(&o)
 	}
+
+	return o
+}
+
+func (o operations) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	// --- database interaction ---
+	return o.database.ListFleets(ctx)
 }
 
-func (o operations) GetCarsInFleet(ctx context.Context, fleetID model.FleetID) ([]model.CarBase, error) {
+func (o operations) CreateFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error) {
+	// --- database interaction ---
+	return o.database.AddFleet(ctx, fleet)
+}
+
+func (o operations) GetFleet(ctx context.Context, fleetID model.FleetID) (*model.Fleet, error) {
+	// --- database interaction ---
+	return o.database.GetFleet(ctx, fleetID)
+}
+
+func (o operations) UpdateFleet(ctx context.Context, fleetID model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
+
 	// --- database interaction ---
-	vins, err := o.database.GetCarsForFleet(ctx, fleetID)
+	return o.database.UpdateFleet(ctx, fleetID, update)
+}
+
+func (o operations) DeleteFleet(ctx context.Context, fleetID model.FleetID) error {
+	// --- database interaction ---
+	return o.database.DeleteFleet(ctx, fleetID)
+}
+
+func (o operations) GetFleetHistory(ctx context.Context, fleetID model.FleetID,
+	query model.HistoryQuery) (*model.HistoryPage, error) {
+
+	offset, err := decodeCursor(query.Cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	// --- Car service interaction ---
+	limit := defaultHistoryLimit
+	if query.Limit != nil {
+		limit = *query.Limit
+	}
 
-	// create an array to hold the car (base) objects for all those VINs returned by the database
-	cars := make([]model.CarBase, len(vins))
+	// --- database interaction ---
+	// read one more entry than requested to find out whether there is a next page
+	entries, err := o.database.GetFleetHistory(ctx, fleetID, query.From, query.To, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
 
-	// iterate over the VINs and query for the cars respectively
-	for index, vin := range vins {
-		carResponse, err := o.carClient.GetCarWithResponse(ctx, vin)
+	var nextCursor *model.Cursor
+	if len(entries) > limit {
+		entries = entries[:limit]
+		nextCursor = encodeCursor(offset + limit)
+	}
+
+	return &model.HistoryPage{Entries: entries, NextCursor: nextCursor}, nil
+}
+
+func (o operations) GetCarsInFleet(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.CarPage, error) {
+
+	page, _, err := o.queryCarsInFleet(ctx, fleetID, query, false)
+	return page, err
+}
+
+func (o operations) GetCarsInFleetTolerant(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.FleetOverview, error) {
+
+	page, carErrors, err := o.queryCarsInFleet(ctx, fleetID, query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.FleetOverview{
+		Cars:       page.Cars,
+		Errors:     carErrors,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// queryCarsInFleet resolves the page of cars assigned to the given fleet which is selected by the query.
+//
+// If the page only depends on the VINs (no filters, sorted by VIN or not at all), only the VINs of the page are read
+// from the database and resolved. Otherwise, all cars of the fleet have to be resolved to be filtered and sorted; then,
+// errors for cars which could not be resolved (if tolerated) are only reported with the first page.
+func (o operations) queryCarsInFleet(ctx context.Context, fleetID model.FleetID, query model.CarQuery,
+	tolerateErrors bool) (*model.CarPage, []model.CarError, error) {
+
+	offset, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if order, ok := databaseVinOrder(query); ok {
+		// --- database interaction ---
+		// read one more VIN than requested to find out whether there is a next page
+		limit := *query.Limit
+		vins, err := o.database.GetCarsForFleetPage(ctx, fleetID, query.AsOf, order, offset, limit+1)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		if carResponse.JSON200 == nil {
-			// if the retrieval fails for any car, the whole operation fails
-			statusCode := carResponse.StatusCode()
-			if statusCode == http.StatusNotFound {
-				// (car was deleted since it was added to the fleet -> fleet database references unknown data)
-				// this error by the domain is known but results from an inconsistency
-				return nil, fmt.Errorf("%w: car %s from fleet %s not in domain", fleetErrors.ErrDomainAssertion, vin, fleetID)
+		var nextCursor *model.Cursor
+		if len(vins) > limit {
+			vins = vins[:limit]
+			nextCursor = encodeCursor(offset + limit)
+		}
+
+		// --- Car service interaction ---
+		cars, carErrors, err := o.getCarsFromDomain(ctx, fleetID, vins, tolerateErrors)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &model.CarPage{Cars: cars, NextCursor: nextCursor}, carErrors, nil
+	}
+
+	// --- database interaction ---
+	vins, err := o.database.GetCarsForFleet(ctx, fleetID, query.AsOf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// --- Car service interaction ---
+	cars, carErrors, err := o.getCarsFromDomain(ctx, fleetID, vins, tolerateErrors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cars = filterCars(cars, query)
+	sortCars(cars, query)
+	pageCars, nextCursor := paginate(cars, offset, query.Limit)
+
+	if offset > 0 {
+		// the errors have already been reported with the first page
+		carErrors = []model.CarError{}
+	}
+
+	return &model.CarPage{Cars: pageCars, NextCursor: nextCursor}, carErrors, nil
+}
+
+// getCarsFromDomain queries the Car service for the cars with the given VINs (assigned to the given fleet).
+// At most carRequestConcurrency requests are performed at the same time. The returned cars have the same order as
+// the given VINs.
+//
+// Every car is requested individually: the Car service only lists the VINs of its cars, so listing them cannot
+// replace these requests.
+//
+// If tolerateErrors is false and the retrieval fails for any car, the whole operation fails and all outstanding
+// requests are cancelled. Otherwise, the cars which could not be retrieved are skipped and one error is returned for
+// each of them (in the same order). Even then, the operation fails if the given context is done.
+func (o operations) getCarsFromDomain(ctx context.Context, fleetID model.FleetID, vins []model.Vin,
+	tolerateErrors bool) ([]model.CarBase, []model.CarError, error) {
+
+	// create arrays to hold the car (base) objects or errors for all those VINs returned by the database
+	cars := make([]*model.CarBase, len(vins))
+	carErrors := make([]error, len(vins))
+
+	// the group context is cancelled as soon as the first request fails (which only happens if errors are not
+	// tolerated)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(o.carRequestConcurrency)
+
+	// only the base data is returned, so cached cars with outdated dynamic data are fine
+	staticCtx := dcar.WithStaticDataSufficient(groupCtx)
+
+	// query for the cars respectively (Go blocks while the maximum number of requests is in flight)
+	for index, vin := range vins {
+		index, vin := index, vin
+		group.Go(func() error {
+			// remark: every goroutine writes to its own index, so no synchronization is required
+			cars[index], carErrors[index] = o.getCarFromDomain(staticCtx, fleetID, vin)
+			if tolerateErrors {
+				return nil
 			}
-			return nil, fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+			return carErrors[index]
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+	// errors caused by the request being cancelled are not specific to individual cars
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// collect the results in the order of the VINs (always non-nil to be serialized as empty arrays)
+	resolvedCars := make([]model.CarBase, 0, len(vins))
+	resolveErrors := make([]model.CarError, 0)
+	for index, vin := range vins {
+		if carErrors[index] != nil {
+			message := carErrors[index].Error()
+			if !errors.Is(carErrors[index], fleetErrors.ErrDomainAssertion) {
+				// other errors (e.g. connection failures) may contain internal details -> do not expose them
+				message = carUnavailableMessage
+			}
+
+			resolveErrors = append(resolveErrors, model.CarError{
+				Vin:     vin,
+				Message: message,
+			})
+			continue
 		}
+		resolvedCars = append(resolvedCars, *cars[index])
+	}
+
+	return resolvedCars, resolveErrors, nil
+}
+
+// getCarFromDomain queries the Car service for the car with the given VIN which is assigned to the given fleet
+func (o operations) getCarFromDomain(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.CarBase, error) {
+	carResponse, err := o.carClient.GetCarWithResponse(ctx, vin)
+	if err != nil {
+		return nil, err
+	}
 
-		// if the car data could be retrieved -> add the data to the array
-		// remark: index just increases in every loop, so it's writing successive values to the array
-		cars[index] = dcar.ToModelBaseFromCar(carResponse.JSON200)
+	if carResponse.JSON200 == nil {
+		statusCode := carResponse.StatusCode()
+		if statusCode == http.StatusNotFound {
+			return nil, carNotInDomainError(fleetID, vin)
+		}
+		return nil, fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
 	}
 
-	return cars, nil
+	// if the car data could be retrieved -> return the base data
+	car := dcar.ToModelBaseFromCar(carResponse.JSON200)
+	return &car, nil
+}
+
+// carNotInDomainError reports that the given car assigned to the given fleet is not known to the Car service
+func carNotInDomainError(fleetID model.FleetID, vin model.Vin) error {
+	// (car was deleted since it was added to the fleet -> fleet database references unknown data)
+	// this error by the domain is known but results from an inconsistency
+	return fmt.Errorf("%w: car %s from fleet %s not in domain", fleetErrors.ErrDomainAssertion, vin, fleetID)
+}
+
+// listCarsFromDomain queries the Car service for the VINs of all cars it knows
+func (o operations) listCarsFromDomain(ctx context.Context) (map[model.Vin]bool, error) {
+	carsResponse, err := o.carClient.GetCarsWithResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if carsResponse.JSON200 == nil {
+		return nil, fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion,
+			carsResponse.StatusCode())
+	}
+
+	knownVins := make(map[model.Vin]bool, len(*carsResponse.JSON200))
+	for _, vin := range *carsResponse.JSON200 {
+		knownVins[vin] = true
+	}
+	return knownVins, nil
 }
 
 func (o operations) RemoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) error {
 	// --- database interaction ---
-	return o.database.RemoveCarFromFleet(ctx, fleetID, vin)
+	if err := o.database.RemoveCarFromFleet(ctx, fleetID, vin); err != nil {
+		return err
+	}
+
+	// the data of the car is not required any longer if the Car service client caches it
+	if invalidator, ok := o.carClient.(dcar.CacheInvalidator); ok {
+		invalidator.Invalidate(vin)
+	}
+	return nil
 }
 
 func (o operations) GetCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.Car, error) {
@@ -116,7 +385,8 @@ func (o operations) GetCar(ctx context.Context, fleetID model.FleetID, vin model
 func (o operations) AddCarToFleet(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.CarBase, error) {
 	// --- Car service interaction ---
 	// check for the car first to prevent VINs which no cars are known for to be stored in the database
-	carResponse, err := o.carClient.GetCarWithResponse(ctx, vin)
+	// (only the base data is returned, so cached cars with outdated dynamic data are fine)
+	carResponse, err := o.carClient.GetCarWithResponse(dcar.WithStaticDataSufficient(ctx), vin)
 	if err != nil {
 		return nil, err
 	}
@@ -141,3 +411,112 @@ func (o operations) AddCarToFleet(ctx context.Context, fleetID model.FleetID, vi
 	baseData := dcar.ToModelBaseFromCar(carResponse.JSON200)
 	return &baseData, nil
 }
+
+func (o operations) CreateCarInFleet(ctx context.Context, fleetID model.FleetID,
+	car model.Car) (*model.CarBase, error) {
+
+	// --- database interaction ---
+	// check for the fleet first to prevent creating cars which cannot be assigned anyway
+	if _, err := o.database.GetFleet(ctx, fleetID); err != nil {
+		return nil, err
+	}
+
+	// --- Car service interaction ---
+	domainCar := dcar.NewCarFromModel(&car)
+	addResponse, err := o.carClient.AddVehicleWithResponse(ctx, domainCar)
+	if err != nil {
+		return nil, err
+	}
+
+	if addResponse.JSON201 == nil {
+		statusCode := addResponse.StatusCode()
+		if statusCode == http.StatusConflict {
+			// it is a defined error of this operation that the car already exists
+			return nil, fmt.Errorf("%w: %s", fleetErrors.ErrCarAlreadyExists, car.Vin)
+		}
+		return nil, fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+	}
+
+	// --- database interaction ---
+	err = o.database.AddCarToFleet(ctx, fleetID, car.Vin)
+	if err != nil {
+		// the car is deleted again so that the Car service does not keep a car which is not assigned to any fleet
+		o.deleteCreatedCar(ctx, fleetID, car.Vin)
+		return nil, err
+	}
+
+	baseData := dcar.ToModelBaseFromCar(&domainCar)
+	return &baseData, nil
+}
+
+// deleteCreatedCar deletes the car with the given VIN which was created for the given fleet but could not be assigned
+// to it. The deletion is completed even if the caller gives up in the meantime. If it fails, the car remains in the
+// Car service, which is logged.
+func (o operations) deleteCreatedCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) {
+	deleteResponse, err := o.carClient.DeleteCarWithResponse(resilience.WithoutCancel(ctx), vin)
+	if err == nil && deleteResponse.StatusCode() != http.StatusNoContent {
+		err = fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion,
+			deleteResponse.StatusCode())
+	}
+	if err != nil {
+		log.Printf("car %s created for fleet %s could not be deleted after its assignment failed: %v", vin, fleetID,
+			err)
+	}
+}
+
+func (o operations) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	// --- Rental management service interaction ---
+	// cars which are rented or about to be rented must stay available
+	rentalResponse, err := o.rentalManagementClient.GetNextRentalWithResponse(ctx, vin)
+	if err != nil {
+		return err
+	}
+	if rentalResponse.JSON200 != nil {
+		return fmt.Errorf("%w: %s (rental %s)", fleetErrors.ErrCarRented, vin, rentalResponse.JSON200.Id)
+	}
+	if rentalResponse.StatusCode() != http.StatusNoContent {
+		return fmt.Errorf("%w: error code %d", fleetErrors.ErrRentalManagementAssertion, rentalResponse.StatusCode())
+	}
+
+	// --- Car service interaction ---
+	// a car which has already been deleted in the Car service (e.g. by a previous attempt whose response was lost)
+	// is still removed from its fleets, so that it does not stay assigned to them
+	carResponse, err := o.carClient.GetCarWithResponse(ctx, vin)
+	if err != nil {
+		return err
+	}
+	statusCode := carResponse.StatusCode()
+	if carResponse.JSON200 == nil && statusCode != http.StatusNotFound {
+		return fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+	}
+
+	// --- database interaction ---
+	// the car is removed from the fleets before it is deleted, so that a failed deletion can simply be retried
+	if err := o.database.DecommissionCar(ctx, vin, reason); err != nil {
+		return err
+	}
+	if carResponse.JSON200 == nil {
+		return nil
+	}
+
+	// --- Car service interaction ---
+	deleteResponse, err := o.carClient.DeleteCarWithResponse(ctx, vin)
+	if err != nil {
+		return err
+	}
+	// a car which has been deleted in the meantime is decommissioned as well
+	if statusCode := deleteResponse.StatusCode(); statusCode != http.StatusNoContent &&
+		statusCode != http.StatusNotFound {
+
+		return fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+	}
+	return nil
+}
+
+func (o operations) MoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin,
+	targetFleetID model.FleetID) error {
+
+	// --- database interaction ---
+	// the car is already known as it is assigned to the fleet, so the Car service is not queried again
+	return o.database.MoveCarToFleet(ctx, fleetID, vin, targetFleetID)
+}
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"PFleetManagement/infrastructure/database"
 	"PFleetManagement/infrastructure/dcar"
 	rentalManagement "PFleetManagement/infrastructure/rentalmanagement"
 	"PFleetManagement/logic/fleetErrors"
@@ -10,11 +11,14 @@ import (
 	"PFleetManagement/mocks/rentalmanagementmocks"
 	"context"
 	"errors"
+	"fmt"
 	carTypes "github.com/ccsapp/cargotypes"
 	openapiTypes "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"regexp"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -70,6 +74,9 @@ var modelCar1NoRental = model.Car{
 	Vin: "3B7HF13Y81G193584",
 }
 
+// testVinPattern is the format of a VIN as defined by the OpenAPI spec (see api.VinPattern)
+var testVinPattern = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{13}[0-9]{4}$`)
+
 var vins = []model.Vin{
 	"3B7HF13Y81G193584",
 }
@@ -78,6 +85,49 @@ var cars = []model.CarBase{
 	carBase1,
 }
 
+var fleetCreation1 = model.FleetCreation{
+	FleetID: "jJd9jb8I",
+	Name:    "Karlsruhe Central Station",
+	Owner:   "KIT Mobility",
+	Labels:  map[string]string{"region": "south"},
+}
+
+var fleet1 = model.Fleet{
+	CreatedAt: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+	FleetID:   "jJd9jb8I",
+	Name:      "Karlsruhe Central Station",
+	Owner:     "KIT Mobility",
+	Labels:    map[string]string{"region": "south"},
+	UpdatedAt: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// testContextKey is the key of a value identifying the context of a test
+type testContextKey struct{}
+
+// newTestContext creates a context which can be recognized by derivedFrom
+func newTestContext(t *testing.T) context.Context {
+	return context.WithValue(context.Background(), testContextKey{}, t.Name())
+}
+
+type derivedContextMatcher struct {
+	parent context.Context
+}
+
+// derivedFrom matches a context which is the given (test) context itself or derived from it, e.g. to be
+// cancelled independently. The given context must have been created with newTestContext.
+func derivedFrom(parent context.Context) gomock.Matcher {
+	return derivedContextMatcher{parent}
+}
+
+func (m derivedContextMatcher) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx.Value(testContextKey{}) == m.parent.Value(testContextKey{})
+}
+
+func (m derivedContextMatcher) String() string {
+	return fmt.Sprintf("is a context derived from %v", m.parent)
+}
+
 func TestOperations_AddCarToFleet_success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -85,7 +135,7 @@ func TestOperations_AddCarToFleet_success(t *testing.T) {
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -93,7 +143,7 @@ func TestOperations_AddCarToFleet_success(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
 		JSON200: &car1,
 	}, nil)
 	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(nil)
@@ -111,7 +161,7 @@ func TestOperations_AddCarToFleet_domainError(t *testing.T) {
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -120,7 +170,7 @@ func TestOperations_AddCarToFleet_domainError(t *testing.T) {
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 	domainError := errors.New("domain error")
 
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(nil, domainError)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(nil, domainError)
 
 	carBase, err := operations.AddCarToFleet(ctx, fleetID, vin)
 
@@ -135,7 +185,7 @@ func TestOperations_AddCarToFleet_carNotFound(t *testing.T) {
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193585"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -143,7 +193,7 @@ func TestOperations_AddCarToFleet_carNotFound(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
 		HTTPResponse: &http.Response{
 			StatusCode: http.StatusNotFound,
 		},
@@ -162,7 +212,7 @@ func TestOperations_AddCarToFleet_unexpectedDomainStatusCode(t *testing.T) {
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193585"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -170,7 +220,7 @@ func TestOperations_AddCarToFleet_unexpectedDomainStatusCode(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
 		HTTPResponse: &http.Response{
 			StatusCode: http.StatusTeapot,
 		},
@@ -189,7 +239,7 @@ func TestOperations_AddCarToFleet_databaseError(t *testing.T) {
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -199,7 +249,7 @@ func TestOperations_AddCarToFleet_databaseError(t *testing.T) {
 
 	databaseError := errors.New("database error")
 
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
 		JSON200: &car1,
 	}, nil)
 	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(databaseError)
@@ -210,14 +260,14 @@ func TestOperations_AddCarToFleet_databaseError(t *testing.T) {
 	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_success_rentalExists(t *testing.T) {
+func TestOperations_CreateCarInFleet_success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -225,31 +275,24 @@ func TestOperations_GetCar_success_rentalExists(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		JSON200: &car1,
-	}, nil)
-	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
-		HTTPResponse: &http.Response{
-			StatusCode: http.StatusOK,
-		},
-		JSON200: &rental1,
-	}, nil)
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&model.Fleet{FleetID: fleetID}, nil)
+	mockCar.EXPECT().AddVehicleWithResponse(ctx, dcar.NewCarFromModel(&modelCar1NoRental)).
+		Return(&dcar.AddVehicleResponse{JSON201: &vin}, nil)
+	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(nil)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
 	assert.Nil(t, err)
-	assert.Equal(t, &modelCar1, car)
+	assert.Equal(t, &carBase1, carBase)
 }
 
-func TestOperations_GetCar_success_noRentalExists(t *testing.T) {
+func TestOperations_CreateCarInFleet_fleetNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
-	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -257,30 +300,22 @@ func TestOperations_GetCar_success_noRentalExists(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		JSON200: &car1,
-	}, nil)
-	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
-		HTTPResponse: &http.Response{
-			StatusCode: http.StatusNoContent,
-		},
-	}, nil)
+	// the car is not created as it could not be assigned anyway
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(nil, fleetErrors.ErrFleetNotFound)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
-	assert.Nil(t, err)
-	assert.Equal(t, &modelCar1NoRental, car)
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_databaseError(t *testing.T) {
+func TestOperations_CreateCarInFleet_carAlreadyExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
-	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -288,24 +323,24 @@ func TestOperations_GetCar_databaseError(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	databaseError := errors.New("database error")
-
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(false, databaseError)
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&model.Fleet{FleetID: fleetID}, nil)
+	mockCar.EXPECT().AddVehicleWithResponse(ctx, gomock.Any()).Return(&dcar.AddVehicleResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusConflict},
+	}, nil)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
-	assert.ErrorIs(t, err, databaseError)
-	assert.Nil(t, car)
+	assert.ErrorIs(t, err, fleetErrors.ErrCarAlreadyExists)
+	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_notInFleet(t *testing.T) {
+func TestOperations_CreateCarInFleet_unexpectedDomainStatusCode(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
-	vin := "3B7HF13Y81G193585"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -313,22 +348,26 @@ func TestOperations_GetCar_notInFleet(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(false, nil)
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&model.Fleet{FleetID: fleetID}, nil)
+	mockCar.EXPECT().AddVehicleWithResponse(ctx, gomock.Any()).Return(&dcar.AddVehicleResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest},
+	}, nil)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
-	assert.ErrorIs(t, err, fleetErrors.ErrCarNotInFleet)
-	assert.Nil(t, car)
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_domainError(t *testing.T) {
+func TestOperations_CreateCarInFleet_assignmentFailed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	// the car is deleted again even if the caller already gave up
+	ctx, cancel := context.WithCancel(newTestContext(t))
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -336,25 +375,35 @@ func TestOperations_GetCar_domainError(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	domainError := errors.New("domain error")
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&model.Fleet{FleetID: fleetID}, nil)
+	mockCar.EXPECT().AddVehicleWithResponse(ctx, gomock.Any()).Return(&dcar.AddVehicleResponse{JSON201: &vin}, nil)
+	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).
+		DoAndReturn(func(context.Context, model.FleetID, model.Vin) error {
+			cancel()
+			return fleetErrors.ErrCarAssignedToOtherFleet
+		})
+	mockCar.EXPECT().DeleteCarWithResponse(derivedFrom(ctx), vin).
+		DoAndReturn(func(ctx context.Context, _ carTypes.VinParam,
+			_ ...dcar.RequestEditorFn) (*dcar.DeleteCarResponse, error) {
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(nil, domainError)
+			assert.Nil(t, ctx.Err())
+			return &dcar.DeleteCarResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+		})
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
-	assert.ErrorIs(t, err, domainError)
-	assert.Nil(t, car)
+	assert.ErrorIs(t, err, fleetErrors.ErrCarAssignedToOtherFleet)
+	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_rentalManagementError(t *testing.T) {
+func TestOperations_CreateCarInFleet_compensationFailed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -362,28 +411,42 @@ func TestOperations_GetCar_rentalManagementError(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	rmError := errors.New("RentalManagement error")
+	databaseError := errors.New("database error")
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		JSON200: &car1,
-	}, nil)
-	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(nil, rmError)
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&model.Fleet{FleetID: fleetID}, nil)
+	mockCar.EXPECT().AddVehicleWithResponse(ctx, gomock.Any()).Return(&dcar.AddVehicleResponse{JSON201: &vin}, nil)
+	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(databaseError)
+	mockCar.EXPECT().DeleteCarWithResponse(derivedFrom(ctx), vin).Return(nil, fleetErrors.ErrServiceUnavailable)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	carBase, err := operations.CreateCarInFleet(ctx, fleetID, modelCar1NoRental)
 
-	assert.ErrorIs(t, err, rmError)
-	assert.Nil(t, car)
+	// the reason why the car could not be assigned is reported, not the failed compensation
+	assert.ErrorIs(t, err, databaseError)
+	assert.NotErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+	assert.Nil(t, carBase)
 }
 
-func TestOperations_GetCar_unexpectedDomainStatusCode(t *testing.T) {
+// expectDecommissioning sets up the expectations of a decommissioning which passes the rental and existence checks
+func expectDecommissioning(ctx context.Context, vin model.Vin, mockCar *carmocks.MockClientWithResponsesInterface,
+	mockRentalManagement *rentalmanagementmocks.MockClientWithResponsesInterface) {
+
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNoContent,
+		},
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+}
+
+func TestOperations_DecommissionCar_success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -391,27 +454,26 @@ func TestOperations_GetCar_unexpectedDomainStatusCode(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		HTTPResponse: &http.Response{
-			StatusCode: http.StatusTeapot,
-		},
-	}, nil)
+	expectDecommissioning(ctx, vin, mockCar, mockRentalManagement)
+	gomock.InOrder(
+		mockDatabase.EXPECT().DecommissionCar(ctx, vin, "total loss").Return(nil),
+		mockCar.EXPECT().DeleteCarWithResponse(ctx, vin).Return(&dcar.DeleteCarResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusNoContent},
+		}, nil),
+	)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
-	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
-	assert.Nil(t, car)
+	assert.Nil(t, err)
 }
 
-func TestOperations_GetCar_unexpectedRentalManagementStatusCode(t *testing.T) {
+func TestOperations_DecommissionCar_alreadyDeleted(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -419,30 +481,24 @@ func TestOperations_GetCar_unexpectedRentalManagementStatusCode(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		JSON200: &car1,
-	}, nil)
-	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
-		HTTPResponse: &http.Response{
-			StatusCode: http.StatusTeapot,
-		},
+	expectDecommissioning(ctx, vin, mockCar, mockRentalManagement)
+	mockDatabase.EXPECT().DecommissionCar(ctx, vin, "total loss").Return(nil)
+	mockCar.EXPECT().DeleteCarWithResponse(ctx, vin).Return(&dcar.DeleteCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
 	}, nil)
 
-	car, err := operations.GetCar(ctx, fleetID, vin)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
-	assert.ErrorIs(t, err, fleetErrors.ErrRentalManagementAssertion)
-	assert.Nil(t, car)
+	assert.Nil(t, err)
 }
 
-func TestOperations_RemoveCar_success(t *testing.T) {
+func TestOperations_DecommissionCar_carRented(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -450,45 +506,50 @@ func TestOperations_RemoveCar_success(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().RemoveCarFromFleet(ctx, fleetID, vin).Return(nil)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusOK,
+		},
+		JSON200: &rental1,
+	}, nil)
 
-	err := operations.RemoveCar(ctx, fleetID, vin)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, fleetErrors.ErrCarRented)
 }
 
-func TestOperations_RemoveCar_databaseError(t *testing.T) {
+func TestOperations_DecommissionCar_unexpectedRentalManagementStatusCode(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
 	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
 
-	databaseError := errors.New("database error")
-
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().RemoveCarFromFleet(ctx, fleetID, vin).Return(databaseError)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+		},
+	}, nil)
 
-	err := operations.RemoveCar(ctx, fleetID, vin)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
-	assert.ErrorIs(t, err, databaseError)
+	assert.ErrorIs(t, err, fleetErrors.ErrRentalManagementAssertion)
 }
 
-func TestOperations_GetCarsInFleet_success(t *testing.T) {
+func TestOperations_DecommissionCar_deletedInCarService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
@@ -496,68 +557,75 @@ func TestOperations_GetCarsInFleet_success(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(vins, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
-		JSON200: &car1,
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNoContent,
+		},
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
 	}, nil)
+	// the car is still removed from its fleets (e.g. when retrying after a lost response), but not deleted again
+	mockDatabase.EXPECT().DecommissionCar(ctx, vin, "total loss").Return(nil)
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
 	assert.Nil(t, err)
-	assert.Equal(t, cars, retCars)
 }
 
-func TestOperations_GetCarsInFleet_databaseError(t *testing.T) {
+func TestOperations_DecommissionCar_databaseError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
 	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
 
-	databaseError := errors.New("database error")
-
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(nil, databaseError)
+	databaseError := errors.New("database error")
+
+	// the car is not deleted if it could not be removed from its fleets
+	expectDecommissioning(ctx, vin, mockCar, mockRentalManagement)
+	mockDatabase.EXPECT().DecommissionCar(ctx, vin, "total loss").Return(databaseError)
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
 	assert.ErrorIs(t, err, databaseError)
-	assert.Nil(t, retCars)
 }
 
-func TestOperations_GetCarsInFleet_domainError(t *testing.T) {
+func TestOperations_DecommissionCar_unexpectedDomainStatusCode(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	fleetID := "jJd9jb8I"
 	vin := "3B7HF13Y81G193584"
 
-	ctx := context.Background()
+	ctx := newTestContext(t)
 
 	mockDatabase := mocks.NewMockFleetDB(ctrl)
 	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
 	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
 
-	domainError := errors.New("domain error")
-
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(vins, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(nil, domainError)
+	expectDecommissioning(ctx, vin, mockCar, mockRentalManagement)
+	mockDatabase.EXPECT().DecommissionCar(ctx, vin, "total loss").Return(nil)
+	mockCar.EXPECT().DeleteCarWithResponse(ctx, vin).Return(&dcar.DeleteCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+	}, nil)
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	err := operations.DecommissionCar(ctx, vin, "total loss")
 
-	assert.ErrorIs(t, err, domainError)
-	assert.Nil(t, retCars)
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
 }
 
-func TestOperations_GetCarsInFleet_carNotFound(t *testing.T) {
+func TestOperations_GetCar_success_rentalExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -572,20 +640,24 @@ func TestOperations_GetCarsInFleet_carNotFound(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(vins, nil)
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
 	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
 		HTTPResponse: &http.Response{
-			StatusCode: http.StatusNotFound,
+			StatusCode: http.StatusOK,
 		},
+		JSON200: &rental1,
 	}, nil)
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	car, err := operations.GetCar(ctx, fleetID, vin)
 
-	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
-	assert.Nil(t, retCars)
+	assert.Nil(t, err)
+	assert.Equal(t, &modelCar1, car)
 }
 
-func TestOperations_GetCarsInFleet_unexpectedDomainStatusCode(t *testing.T) {
+func TestOperations_GetCar_success_noRentalExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -600,27 +672,28 @@ func TestOperations_GetCarsInFleet_unexpectedDomainStatusCode(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(vins, nil)
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
 	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
 		HTTPResponse: &http.Response{
-			StatusCode: http.StatusTeapot,
+			StatusCode: http.StatusNoContent,
 		},
 	}, nil)
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	car, err := operations.GetCar(ctx, fleetID, vin)
 
-	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
-	assert.Nil(t, retCars)
+	assert.Nil(t, err)
+	assert.Equal(t, &modelCar1NoRental, car)
 }
 
-func TestOperations_GetCarsInFleet_secondDomainCallFailed(t *testing.T) {
+func TestOperations_GetCar_databaseError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	fleetID := "jJd9jb8I"
-	vin1 := "3B7HF13Y81G193584"
-	vin2 := "3B7HF13Y81G193585"
-	multipleVins := []string{vin1, vin2}
+	vin := "3B7HF13Y81G193584"
 
 	ctx := context.Background()
 
@@ -630,21 +703,1683 @@ func TestOperations_GetCarsInFleet_secondDomainCallFailed(t *testing.T) {
 
 	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
 
-	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID).Return(multipleVins, nil)
-	firstCall := mockCar.EXPECT().GetCarWithResponse(ctx, vin1).
-		Return(&dcar.GetCarResponse{
-			JSON200: &car1,
-		}, nil)
-	mockCar.EXPECT().GetCarWithResponse(ctx, vin2).
-		After(firstCall).
-		Return(&dcar.GetCarResponse{
-			HTTPResponse: &http.Response{
-				StatusCode: http.StatusNotFound,
-			},
-		}, nil)
+	databaseError := errors.New("database error")
 
-	retCars, err := operations.GetCarsInFleet(ctx, fleetID)
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(false, databaseError)
 
-	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
-	assert.Nil(t, retCars)
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, databaseError)
+	assert.Nil(t, car)
+}
+
+func TestOperations_GetCar_notInFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193585"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(false, nil)
+
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrCarNotInFleet)
+	assert.Nil(t, car)
+}
+
+func TestOperations_GetCar_domainError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	domainError := errors.New("domain error")
+
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(nil, domainError)
+
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, domainError)
+	assert.Nil(t, car)
+}
+
+func TestOperations_GetCar_rentalManagementError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	rmError := errors.New("RentalManagement error")
+
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(nil, rmError)
+
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, rmError)
+	assert.Nil(t, car)
+}
+
+func TestOperations_GetCar_unexpectedDomainStatusCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusTeapot,
+		},
+	}, nil)
+
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, car)
+}
+
+func TestOperations_GetCar_unexpectedRentalManagementStatusCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().IsCarInFleet(ctx, fleetID, vin).Return(true, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockRentalManagement.EXPECT().GetNextRentalWithResponse(ctx, vin).Return(&rentalManagement.GetNextRentalResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusTeapot,
+		},
+	}, nil)
+
+	car, err := operations.GetCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrRentalManagementAssertion)
+	assert.Nil(t, car)
+}
+
+func TestOperations_RemoveCar_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().RemoveCarFromFleet(ctx, fleetID, vin).Return(nil)
+
+	err := operations.RemoveCar(ctx, fleetID, vin)
+
+	assert.Nil(t, err)
+}
+
+func TestOperations_RemoveCar_invalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	cachingClient := dcar.NewCachingClient(mockCar, dcar.CacheConfig{
+		StaticTTL:  time.Hour,
+		DynamicTTL: time.Hour,
+		MaxEntries: 10,
+	})
+	operations := NewOperations(mockDatabase, cachingClient, mockRentalManagement)
+
+	// the car is requested again after it was removed from the fleet and thus from the cache
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &car1,
+	}, nil).Times(2)
+	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(nil).Times(2)
+	mockDatabase.EXPECT().RemoveCarFromFleet(ctx, fleetID, vin).Return(nil)
+
+	_, err := operations.AddCarToFleet(ctx, fleetID, vin)
+	assert.Nil(t, err)
+
+	err = operations.RemoveCar(ctx, fleetID, vin)
+	assert.Nil(t, err)
+
+	_, err = operations.AddCarToFleet(ctx, fleetID, vin)
+	assert.Nil(t, err)
+}
+
+func TestOperations_RemoveCar_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	databaseError := errors.New("database error")
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().RemoveCarFromFleet(ctx, fleetID, vin).Return(databaseError)
+
+	err := operations.RemoveCar(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, databaseError)
+}
+
+func TestOperations_GetCarsInFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(vins, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, cars, retPage.Cars)
+}
+
+func TestOperations_GetCarsInFleet_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	databaseError := errors.New("database error")
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(nil, databaseError)
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, databaseError)
+	assert.Nil(t, retCars)
+}
+
+func TestOperations_GetCarsInFleet_domainError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	domainError := errors.New("domain error")
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(vins, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(nil, domainError)
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, domainError)
+	assert.Nil(t, retCars)
+}
+
+func TestOperations_GetCarsInFleet_carNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(vins, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
+	}, nil)
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, retCars)
+}
+
+func TestOperations_GetCarsInFleet_unexpectedDomainStatusCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(vins, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusTeapot,
+		},
+	}, nil)
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, retCars)
+}
+
+func TestOperations_GetCarsInFleet_secondDomainCallFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	multipleVins := []string{vin1, vin2}
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(multipleVins, nil)
+	firstCall := mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin1).
+		Return(&dcar.GetCarResponse{
+			JSON200: &car1,
+		}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin2).
+		After(firstCall).
+		Return(&dcar.GetCarResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusNotFound,
+			},
+		}, nil)
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, retCars)
+}
+
+func TestOperations_ListFleets_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	fleets := []model.Fleet{fleet1}
+
+	mockDatabase.EXPECT().ListFleets(ctx).Return(fleets, nil)
+
+	retFleets, err := operations.ListFleets(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, fleets, retFleets)
+}
+
+func TestOperations_ListFleets_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	databaseError := errors.New("database error")
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().ListFleets(ctx).Return(nil, databaseError)
+
+	retFleets, err := operations.ListFleets(ctx)
+
+	assert.ErrorIs(t, err, databaseError)
+	assert.Nil(t, retFleets)
+}
+
+func TestOperations_CreateFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().AddFleet(ctx, fleetCreation1).Return(&fleet1, nil)
+
+	fleet, err := operations.CreateFleet(ctx, fleetCreation1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &fleet1, fleet)
+}
+
+func TestOperations_CreateFleet_alreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().AddFleet(ctx, fleetCreation1).Return(nil, fleetErrors.ErrFleetAlreadyExists)
+
+	fleet, err := operations.CreateFleet(ctx, fleetCreation1)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetAlreadyExists)
+	assert.Nil(t, fleet)
+}
+
+func TestOperations_UpdateFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	name := "Karlsruhe Central Station"
+	update := model.FleetUpdate{Name: &name}
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().UpdateFleet(ctx, fleetID, update).Return(&fleet1, nil)
+
+	fleet, err := operations.UpdateFleet(ctx, fleetID, update)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &fleet1, fleet)
+}
+
+func TestOperations_UpdateFleet_notFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	update := model.FleetUpdate{}
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().UpdateFleet(ctx, fleetID, update).Return(nil, fleetErrors.ErrFleetNotFound)
+
+	fleet, err := operations.UpdateFleet(ctx, fleetID, update)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, fleet)
+}
+
+func TestOperations_GetFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&fleet1, nil)
+
+	fleet, err := operations.GetFleet(ctx, fleetID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &fleet1, fleet)
+}
+
+func TestOperations_GetFleet_notFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(nil, fleetErrors.ErrFleetNotFound)
+
+	fleet, err := operations.GetFleet(ctx, fleetID)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, fleet)
+}
+
+func TestOperations_DeleteFleet_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().DeleteFleet(ctx, fleetID).Return(nil)
+
+	err := operations.DeleteFleet(ctx, fleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestOperations_DeleteFleet_notFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().DeleteFleet(ctx, fleetID).Return(fleetErrors.ErrFleetNotFound)
+
+	err := operations.DeleteFleet(ctx, fleetID)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+}
+
+func TestOperations_GetCarsInFleet_concurrentPreservesOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	concurrency := 2
+	multipleVins := []string{"3B7HF13Y81G193581", "3B7HF13Y81G193582", "3B7HF13Y81G193583", "3B7HF13Y81G193584"}
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement, WithCarRequestConcurrency(concurrency))
+
+	var inFlight, maxInFlight int32
+	expectedCars := make([]model.CarBase, len(multipleVins))
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(multipleVins, nil)
+	for index, vin := range multipleVins {
+		// later cars respond faster, so that the responses arrive in reverse order
+		delay := time.Duration(len(multipleVins)-index) * 10 * time.Millisecond
+		car := car1
+		car.Vin = vin
+		expectedCars[index] = dcar.ToModelBaseFromCar(&car)
+
+		mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).
+			DoAndReturn(func(context.Context, string, ...dcar.RequestEditorFn) (*dcar.GetCarResponse, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					previousMax := atomic.LoadInt32(&maxInFlight)
+					if current <= previousMax || atomic.CompareAndSwapInt32(&maxInFlight, previousMax, current) {
+						break
+					}
+				}
+
+				time.Sleep(delay)
+				return &dcar.GetCarResponse{JSON200: &car}, nil
+			})
+	}
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedCars, retPage.Cars)
+	assert.LessOrEqual(t, maxInFlight, int32(concurrency))
+	assert.Greater(t, maxInFlight, int32(1))
+}
+
+func TestOperations_GetCarsInFleet_concurrentCancelsOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	multipleVins := []string{vin1, vin2}
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement, WithCarRequestConcurrency(2))
+
+	started := make(chan struct{})
+	var cancelled bool
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(multipleVins, nil)
+	// the first request blocks until it is cancelled
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin1).
+		DoAndReturn(func(requestCtx context.Context, _ string, _ ...dcar.RequestEditorFn) (*dcar.GetCarResponse, error) {
+			close(started)
+			select {
+			case <-requestCtx.Done():
+				cancelled = true
+				return nil, requestCtx.Err()
+			case <-time.After(5 * time.Second):
+				return &dcar.GetCarResponse{JSON200: &car1}, nil
+			}
+		})
+	// the second request fails as soon as the first one is in flight
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin2).
+		DoAndReturn(func(context.Context, string, ...dcar.RequestEditorFn) (*dcar.GetCarResponse, error) {
+			<-started
+			return &dcar.GetCarResponse{
+				HTTPResponse: &http.Response{
+					StatusCode: http.StatusNotFound,
+				},
+			}, nil
+		})
+
+	retCars, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, retCars)
+	// Wait returns only after all requests have returned, so there is no race on cancelled
+	assert.True(t, cancelled)
+}
+
+func TestOperations_GetCarsInFleetTolerant_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	vin3 := "3B7HF13Y81G193586"
+	multipleVins := []string{vin1, vin2, vin3}
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	domainError := errors.New("domain error")
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(multipleVins, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin1).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin2).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin3).Return(nil, domainError)
+
+	overview, err := operations.GetCarsInFleetTolerant(ctx, fleetID, model.CarQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, cars, overview.Cars)
+	if assert.Len(t, overview.Errors, 2) {
+		assert.Equal(t, vin2, overview.Errors[0].Vin)
+		assert.Contains(t, overview.Errors[0].Message, fleetErrors.ErrDomainAssertion.Error())
+		assert.Equal(t, model.CarError{Vin: vin3, Message: carUnavailableMessage}, overview.Errors[1])
+	}
+}
+
+func TestOperations_GetCarsInFleetTolerant_successEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return([]model.Vin{}, nil)
+
+	overview, err := operations.GetCarsInFleetTolerant(ctx, fleetID, model.CarQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.FleetOverview{Cars: []model.CarBase{}, Errors: []model.CarError{}}, overview)
+}
+
+func TestOperations_GetCarsInFleetTolerant_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	databaseError := errors.New("database error")
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(nil, databaseError)
+
+	overview, err := operations.GetCarsInFleetTolerant(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, databaseError)
+	assert.Nil(t, overview)
+}
+
+func TestOperations_GetCarsInFleetTolerant_cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx, cancel := context.WithCancel(newTestContext(t))
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return(vins, nil)
+	// the request is cancelled (e.g. closed by the client) while the car is requested
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).
+		DoAndReturn(func(requestCtx context.Context, _ string, _ ...dcar.RequestEditorFn) (*dcar.GetCarResponse, error) {
+			cancel()
+			return nil, requestCtx.Err()
+		})
+
+	overview, err := operations.GetCarsInFleetTolerant(ctx, fleetID, model.CarQuery{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, overview)
+}
+
+// newCar creates car data for testing the queries of the fleet overview
+func newCar(vin model.Vin, brand string, carModel string, year int) carTypes.Car {
+	return carTypes.Car{
+		Brand: brand,
+		Model: carModel,
+		ProductionDate: openapiTypes.Date{
+			Time: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Vin: vin,
+	}
+}
+
+func TestOperations_GetCarsInFleet_databasePage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	limit := 1
+	sortParam := model.CarSortVinDesc
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	car2 := newCar(vin2, "Audi", "A3", 2017)
+
+	// one more VIN than requested is read to detect the next page, but only the requested cars are resolved
+	mockDatabase.EXPECT().GetCarsForFleetPage(ctx, fleetID, nil, database.VinOrderDescending, 0, 2).
+		Return([]model.Vin{vin2, vin1}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin2).Return(&dcar.GetCarResponse{
+		JSON200: &car2,
+	}, nil)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{
+		Limit: &limit,
+		Sort:  &sortParam,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.CarBase{dcar.ToModelBaseFromCar(&car2)}, retPage.Cars)
+	assert.Equal(t, encodeCursor(1), retPage.NextCursor)
+}
+
+func TestOperations_GetCarsInFleet_databaseLastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+	limit := 2
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleetPage(ctx, fleetID, nil, database.VinOrderAssigned, 1, 3).
+		Return([]model.Vin{vin}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{
+		Cursor: encodeCursor(1),
+		Limit:  &limit,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, cars, retPage.Cars)
+	assert.Nil(t, retPage.NextCursor)
+}
+
+func TestOperations_GetCarsInFleet_databasePageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	limit := 2
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleetPage(ctx, fleetID, nil, database.VinOrderAssigned, 0, 3).
+		Return(nil, fleetErrors.ErrFleetNotFound)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{
+		Limit: &limit,
+	})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, retPage)
+}
+
+func TestOperations_GetCarsInFleet_asOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "WVWZZZ3CZWE689725"
+	limit := 1
+	asOf := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	car1 := newCar(vin1, "Tesla", "Model X", 2022)
+
+	// the cars assigned at the given point in time are resolved with their current data
+	mockDatabase.EXPECT().GetCarsForFleetPage(ctx, fleetID, &asOf, database.VinOrderAssigned, 0, 2).
+		Return([]model.Vin{vin1, vin2}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin1).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{
+		AsOf:  &asOf,
+		Limit: &limit,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.CarBase{dcar.ToModelBaseFromCar(&car1)}, retPage.Cars)
+	assert.Equal(t, encodeCursor(1), retPage.NextCursor)
+}
+
+func TestOperations_GetCarsInFleet_filteredAndSorted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	vin3 := "3B7HF13Y81G193586"
+	vin4 := "3B7HF13Y81G193587"
+	limit := 1
+	brand := "Audi"
+	sortParam := model.CarSortProductionDateDesc
+	productionDateFrom := openapiTypes.Date{Time: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	queriedCars := map[model.Vin]carTypes.Car{
+		vin1: newCar(vin1, "Audi", "A3", 2017),
+		vin2: newCar(vin2, "Tesla", "Model X", 2022),
+		vin3: newCar(vin3, "Audi", "A4", 2019),
+		vin4: newCar(vin4, "Audi", "A1", 2015),
+	}
+
+	// the page depends on the car data -> all cars of the fleet have to be resolved
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return([]model.Vin{vin1, vin2, vin3, vin4}, nil)
+	for vin, car := range queriedCars {
+		car := car
+		mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+			JSON200: &car,
+		}, nil)
+	}
+
+	query := model.CarQuery{
+		Brand:              &brand,
+		Limit:              &limit,
+		ProductionDateFrom: &productionDateFrom,
+		Sort:               &sortParam,
+	}
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, query)
+
+	// only the Audis produced since 2016 match, the newest is returned first
+	expectedCar := queriedCars[vin3]
+	assert.Nil(t, err)
+	assert.Equal(t, []model.CarBase{dcar.ToModelBaseFromCar(&expectedCar)}, retPage.Cars)
+	assert.Equal(t, encodeCursor(1), retPage.NextCursor)
+}
+
+func TestOperations_GetCarsInFleet_invalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	cursor := "bm90LWEtY3Vyc29y"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	retPage, err := operations.GetCarsInFleet(ctx, fleetID, model.CarQuery{
+		Cursor: &cursor,
+	})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrInvalidCursor)
+	assert.Nil(t, retPage)
+}
+
+func TestOperations_GetCarsInFleetTolerant_errorsOnlyOnFirstPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin1 := "3B7HF13Y81G193584"
+	vin2 := "3B7HF13Y81G193585"
+	brand := "Tesla"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, fleetID, nil).Return([]model.Vin{vin1, vin2}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin1).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin2).Return(nil, errors.New("domain error"))
+
+	overview, err := operations.GetCarsInFleetTolerant(ctx, fleetID, model.CarQuery{
+		Brand:  &brand,
+		Cursor: encodeCursor(1),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.FleetOverview{Cars: []model.CarBase{}, Errors: []model.CarError{}}, overview)
+}
+
+func TestOperations_sortCars(t *testing.T) {
+	sortParam := model.CarSortBrand
+	audi1 := dcar.ToModelBaseFromCar(&carTypes.Car{Brand: "Audi", Vin: "3B7HF13Y81G193586"})
+	audi2 := dcar.ToModelBaseFromCar(&carTypes.Car{Brand: "Audi", Vin: "3B7HF13Y81G193585"})
+	tesla := dcar.ToModelBaseFromCar(&carTypes.Car{Brand: "Tesla", Vin: "3B7HF13Y81G193584"})
+
+	sortedCars := []model.CarBase{tesla, audi1, audi2}
+	sortCars(sortedCars, model.CarQuery{Sort: &sortParam})
+
+	// cars with the same brand are ordered by their VIN
+	assert.Equal(t, []model.CarBase{audi2, audi1, tesla}, sortedCars)
+
+	sortParam = model.CarSortBrandDesc
+	sortCars(sortedCars, model.CarQuery{Sort: &sortParam})
+
+	assert.Equal(t, []model.CarBase{tesla, audi1, audi2}, sortedCars)
+}
+
+func TestOperations_decodeCursor(t *testing.T) {
+	offset, err := decodeCursor(encodeCursor(42))
+	assert.Nil(t, err)
+	assert.Equal(t, 42, offset)
+
+	offset, err = decodeCursor(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, offset)
+
+	for _, cursor := range []string{"b2Zmc2V0Oi0x", "b2Zmc2V0OmE", "%%%"} {
+		cursor := cursor
+		_, err = decodeCursor(&cursor)
+		assert.ErrorIs(t, err, fleetErrors.ErrInvalidCursor, cursor)
+	}
+}
+
+func TestOperations_AddCarToFleet_assignedToOtherFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+	mockDatabase.EXPECT().AddCarToFleet(ctx, fleetID, vin).Return(fleetErrors.ErrCarAssignedToOtherFleet)
+
+	carBase, err := operations.AddCarToFleet(ctx, fleetID, vin)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrCarAssignedToOtherFleet)
+	assert.Nil(t, carBase)
+}
+
+func TestOperations_MoveCar_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	targetFleetID := "xk48jpgz"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().MoveCarToFleet(ctx, fleetID, vin, targetFleetID).Return(nil)
+
+	err := operations.MoveCar(ctx, fleetID, vin, targetFleetID)
+
+	assert.Nil(t, err)
+}
+
+func TestOperations_MoveCar_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	targetFleetID := "xk48jpgz"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().MoveCarToFleet(ctx, fleetID, vin, targetFleetID).Return(fleetErrors.ErrCarNotInFleet)
+
+	err := operations.MoveCar(ctx, fleetID, vin, targetFleetID)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrCarNotInFleet)
+}
+
+func TestOperations_AddCarsToFleet_outcomes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	addedVin := "3B7HF13Y81G193584"
+	alreadyInFleetVin := "3B7HF13Y81G193585"
+	otherFleetVin := "3B7HF13Y81G193586"
+	unknownVin := "3B7HF13Y81G193587"
+	invalidVin := "3B7HF13Y81G19358"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement, WithCarRequestConcurrency(2),
+		WithVinPattern(testVinPattern))
+
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&fleet1, nil)
+	for _, vin := range []model.Vin{addedVin, alreadyInFleetVin, otherFleetVin} {
+		mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			JSON200:      &car1,
+		}, nil)
+	}
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), unknownVin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	// neither the invalid nor the unknown car are stored
+	mockDatabase.EXPECT().AddCarsToFleet(ctx, fleetID, []model.Vin{addedVin, alreadyInFleetVin, otherFleetVin}).
+		Return(map[model.Vin]error{
+			alreadyInFleetVin: fleetErrors.ErrCarAlreadyInFleet,
+			otherFleetVin:     fleetErrors.ErrCarAssignedToOtherFleet,
+		}, nil)
+
+	result, err := operations.AddCarsToFleet(ctx, fleetID,
+		[]model.Vin{invalidVin, addedVin, alreadyInFleetVin, addedVin, otherFleetVin, unknownVin})
+
+	assert.Nil(t, err)
+	// repeated VINs are only reported once
+	assert.Equal(t, &model.BatchResult{Results: []model.BatchCarResult{
+		{Vin: invalidVin, Outcome: model.BatchInvalid},
+		{Vin: addedVin, Outcome: model.BatchAdded},
+		{Vin: alreadyInFleetVin, Outcome: model.BatchAlreadyInFleet},
+		{Vin: otherFleetVin, Outcome: model.BatchAssignedToOtherFleet},
+		{Vin: unknownVin, Outcome: model.BatchNotFound},
+	}}, result)
+}
+
+func TestOperations_AddCarsToFleet_unknownFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	// the Car service is not queried at all
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(nil, fleetErrors.ErrFleetNotFound)
+
+	result, err := operations.AddCarsToFleet(ctx, fleetID, vins)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, result)
+}
+
+func TestOperations_AddCarsToFleet_domainError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	// nothing is stored if a car cannot be checked
+	mockDatabase.EXPECT().GetFleet(ctx, fleetID).Return(&fleet1, nil)
+	mockCar.EXPECT().GetCarWithResponse(derivedFrom(ctx), vin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+	}, nil)
+
+	result, err := operations.AddCarsToFleet(ctx, fleetID, []model.Vin{vin})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, result)
+}
+
+func TestOperations_RemoveCarsFromFleet_outcomes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	removedVin := "3B7HF13Y81G193584"
+	notInFleetVin := "3B7HF13Y81G193585"
+	invalidVin := "not a vin"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement, WithVinPattern(testVinPattern))
+
+	mockDatabase.EXPECT().RemoveCarsFromFleet(ctx, fleetID, []model.Vin{removedVin, notInFleetVin}).
+		Return([]model.Vin{removedVin}, nil)
+
+	result, err := operations.RemoveCarsFromFleet(ctx, fleetID, []model.Vin{removedVin, invalidVin, notInFleetVin})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.BatchResult{Results: []model.BatchCarResult{
+		{Vin: removedVin, Outcome: model.BatchRemoved},
+		{Vin: invalidVin, Outcome: model.BatchInvalid},
+		{Vin: notInFleetVin, Outcome: model.BatchNotInFleet},
+	}}, result)
+}
+
+func TestOperations_RemoveCarsFromFleet_withoutVinPattern(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "not a vin"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	// the format of the VIN is not checked
+	mockDatabase.EXPECT().RemoveCarsFromFleet(ctx, fleetID, []model.Vin{vin}).Return(nil, nil)
+
+	result, err := operations.RemoveCarsFromFleet(ctx, fleetID, []model.Vin{vin})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.BatchResult{Results: []model.BatchCarResult{
+		{Vin: vin, Outcome: model.BatchNotInFleet},
+	}}, result)
+}
+
+func TestOperations_RemoveCarsFromFleet_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().RemoveCarsFromFleet(ctx, fleetID, vins).Return(nil, fleetErrors.ErrFleetNotFound)
+
+	result, err := operations.RemoveCarsFromFleet(ctx, fleetID, vins)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, result)
+}
+
+func TestOperations_GetFleetHistory_defaultLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	// one more entry than the default limit indicates a next page
+	entries := make([]model.AuditEntry, defaultHistoryLimit+1)
+	mockDatabase.EXPECT().GetFleetHistory(ctx, fleetID, nil, nil, 0, defaultHistoryLimit+1).Return(entries, nil)
+
+	page, err := operations.GetFleetHistory(ctx, fleetID, model.HistoryQuery{})
+
+	assert.Nil(t, err)
+	assert.Len(t, page.Entries, defaultHistoryLimit)
+	assert.Equal(t, encodeCursor(defaultHistoryLimit), page.NextCursor)
+}
+
+func TestOperations_GetFleetHistory_lastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	vin := "3B7HF13Y81G193584"
+	from := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	limit := 2
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	entries := []model.AuditEntry{
+		{Action: model.AuditCarAdded, FleetID: fleetID, Timestamp: from, Vin: &vin},
+	}
+	mockDatabase.EXPECT().GetFleetHistory(ctx, fleetID, &from, &to, 2, limit+1).Return(entries, nil)
+
+	page, err := operations.GetFleetHistory(ctx, fleetID, model.HistoryQuery{
+		Cursor: encodeCursor(2),
+		From:   &from,
+		Limit:  &limit,
+		To:     &to,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.HistoryPage{Entries: entries}, page)
+}
+
+func TestOperations_GetFleetHistory_invalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+	cursor := "invalid"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	page, err := operations.GetFleetHistory(ctx, fleetID, model.HistoryQuery{Cursor: &cursor})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrInvalidCursor)
+	assert.Nil(t, page)
+}
+
+func TestOperations_GetFleetHistory_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().GetFleetHistory(ctx, fleetID, nil, nil, 0, gomock.Any()).
+		Return(nil, fleetErrors.ErrFleetNotFound)
+
+	page, err := operations.GetFleetHistory(ctx, fleetID, model.HistoryQuery{})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, page)
+}
+
+func TestOperations_GrantRole_success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().SetRole(ctx, fleetID, "bob", model.RoleManager).Return(nil)
+
+	membership, err := operations.GrantRole(ctx, fleetID, "bob", model.RoleManager)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.Membership{Role: model.RoleManager, Subject: "bob"}, membership)
+}
+
+func TestOperations_GrantRole_fleetNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fleetID := "jJd9jb8I"
+
+	ctx := context.Background()
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockDatabase.EXPECT().SetRole(ctx, fleetID, "bob", model.RoleViewer).Return(fleetErrors.ErrFleetNotFound)
+
+	membership, err := operations.GrantRole(ctx, fleetID, "bob", model.RoleViewer)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrFleetNotFound)
+	assert.Nil(t, membership)
+}
+
+func TestOperations_ReconcileFleets_dryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	listedVin := "3B7HF13Y81G193584"
+	createdVin := "3B7HF13Y81G193585"
+	danglingVin := "WVWAA71K08W201030"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockCar.EXPECT().GetCarsWithResponse(ctx).Return(&dcar.GetCarsResponse{
+		JSON200: &[]carTypes.Vin{listedVin},
+	}, nil)
+	mockDatabase.EXPECT().ListFleets(ctx).Return([]model.Fleet{{FleetID: "jJd9jb8I"}, {FleetID: "xk48jpgz"}}, nil)
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, "jJd9jb8I", nil).Return([]model.Vin{listedVin, danglingVin}, nil)
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, "xk48jpgz", nil).Return([]model.Vin{createdVin}, nil)
+	// the cars which are not listed are confirmed individually, as they may have been created after the listing
+	mockCar.EXPECT().GetCarWithResponse(ctx, danglingVin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
+	}, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, createdVin).Return(&dcar.GetCarResponse{
+		JSON200: &car1,
+	}, nil)
+
+	report, err := operations.ReconcileFleets(ctx, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.ReconciliationReport{
+		CheckedCars:   3,
+		CheckedFleets: 2,
+		DanglingCars:  []model.DanglingCar{{FleetID: "jJd9jb8I", Vin: danglingVin}},
+		DryRun:        true,
+	}, report)
+}
+
+func TestOperations_ReconcileFleets_prune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	danglingVin := "WVWAA71K08W201030"
+	removedConcurrentlyVin := "WVWAA71K08W201031"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockCar.EXPECT().GetCarsWithResponse(ctx).Return(&dcar.GetCarsResponse{
+		JSON200: &[]carTypes.Vin{},
+	}, nil)
+	mockDatabase.EXPECT().ListFleets(ctx).Return([]model.Fleet{{FleetID: "jJd9jb8I"}}, nil)
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, "jJd9jb8I", nil).
+		Return([]model.Vin{danglingVin, removedConcurrentlyVin}, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, gomock.Any()).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
+	}, nil).Times(2)
+	mockDatabase.EXPECT().RemoveCarsFromFleet(ctx, "jJd9jb8I", []model.Vin{danglingVin, removedConcurrentlyVin}).
+		Return([]model.Vin{danglingVin}, nil)
+
+	report, err := operations.ReconcileFleets(ctx, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.ReconciliationReport{
+		CheckedCars:   2,
+		CheckedFleets: 1,
+		DanglingCars: []model.DanglingCar{
+			{FleetID: "jJd9jb8I", Pruned: true, Vin: danglingVin},
+			{FleetID: "jJd9jb8I", Vin: removedConcurrentlyVin},
+		},
+	}, report)
+}
+
+func TestOperations_ReconcileFleets_fleetDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockCar.EXPECT().GetCarsWithResponse(ctx).Return(&dcar.GetCarsResponse{
+		JSON200: &[]carTypes.Vin{},
+	}, nil)
+	mockDatabase.EXPECT().ListFleets(ctx).Return([]model.Fleet{{FleetID: "jJd9jb8I"}}, nil)
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, "jJd9jb8I", nil).Return(nil, fleetErrors.ErrFleetNotFound)
+
+	report, err := operations.ReconcileFleets(ctx, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &model.ReconciliationReport{DanglingCars: []model.DanglingCar{}}, report)
+}
+
+func TestOperations_ReconcileFleets_listError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	// without the list of known cars, no car is considered dangling
+	mockCar.EXPECT().GetCarsWithResponse(ctx).Return(nil, fleetErrors.ErrServiceUnavailable)
+
+	report, err := operations.ReconcileFleets(ctx, false)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrServiceUnavailable)
+	assert.Nil(t, report)
+}
+
+func TestOperations_ReconcileFleets_unexpectedDomainStatusCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	danglingVin := "WVWAA71K08W201030"
+
+	ctx := newTestContext(t)
+
+	mockDatabase := mocks.NewMockFleetDB(ctrl)
+	mockCar := carmocks.NewMockClientWithResponsesInterface(ctrl)
+	mockRentalManagement := rentalmanagementmocks.NewMockClientWithResponsesInterface(ctrl)
+
+	operations := NewOperations(mockDatabase, mockCar, mockRentalManagement)
+
+	mockCar.EXPECT().GetCarsWithResponse(ctx).Return(&dcar.GetCarsResponse{
+		JSON200: &[]carTypes.Vin{},
+	}, nil)
+	mockDatabase.EXPECT().ListFleets(ctx).Return([]model.Fleet{{FleetID: "jJd9jb8I"}}, nil)
+	mockDatabase.EXPECT().GetCarsForFleet(ctx, "jJd9jb8I", nil).Return([]model.Vin{danglingVin}, nil)
+	mockCar.EXPECT().GetCarWithResponse(ctx, danglingVin).Return(&dcar.GetCarResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+		},
+	}, nil)
+
+	report, err := operations.ReconcileFleets(ctx, false)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrDomainAssertion)
+	assert.Nil(t, report)
 }
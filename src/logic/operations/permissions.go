@@ -0,0 +1,269 @@
+package operations
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/logic/caller"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"fmt"
+)
+
+// roleRanks orders the roles by their permissions: every role includes the permissions of the lower ranked ones
+var roleRanks = map[model.Role]int{
+	model.RoleViewer:  1,
+	model.RoleManager: 2,
+	model.RoleAdmin:   3,
+}
+
+// permissionChecker is an IOperations decorator which checks the role of the caller (see caller.Info) in the fleets
+// an operation refers to before delegating to the decorated operations
+type permissionChecker struct {
+	next     IOperations
+	database database.FleetDB
+}
+
+// NewPermissionChecker creates an IOperations decorator which only delegates operations to the given IOperations if
+// the caller has a sufficient role in the fleets the operation refers to and fails with fleetErrors.ErrForbidden
+// otherwise. Reading a fleet and its cars requires the viewer role, changing them the manager role and managing the
+// roles of a fleet the admin role.
+//
+// The role of the caller in a fleet is the highest of
+//   - the admin role if the caller is explicitly unrestricted (i.e. requests are deliberately not authenticated) or
+//     has the global admin role according to their token,
+//   - the manager role if the fleet is one of the fleets the caller manages according to their token and
+//   - the role granted to the caller in the fleet, as stored in the database.FleetDB.
+func NewPermissionChecker(next IOperations, fleetDB database.FleetDB) IOperations {
+	return permissionChecker{
+		next:     next,
+		database: fleetDB,
+	}
+}
+
+// isUnrestricted reports whether the caller has the admin role in every fleet
+func isUnrestricted(info caller.Info) bool {
+	return info.Unrestricted || info.Admin
+}
+
+// rankIn returns the rank of the role of the caller in the given fleet or zero if the caller has no role in it
+func (p permissionChecker) rankIn(ctx context.Context, fleetID model.FleetID) (int, error) {
+	info := caller.FromContext(ctx)
+	if isUnrestricted(info) {
+		return roleRanks[model.RoleAdmin], nil
+	}
+
+	rank := 0
+	if info.Manages(fleetID) {
+		rank = roleRanks[model.RoleManager]
+	}
+	if info.Actor == "" {
+		// roles can only be granted to known users
+		return rank, nil
+	}
+
+	role, err := p.database.GetRole(ctx, fleetID, info.Actor)
+	if err != nil {
+		return 0, err
+	}
+	if role != nil && roleRanks[*role] > rank {
+		rank = roleRanks[*role]
+	}
+	return rank, nil
+}
+
+// require fails with fleetErrors.ErrForbidden unless the caller has at least the given role in all given fleets
+func (p permissionChecker) require(ctx context.Context, role model.Role, fleetIDs ...model.FleetID) error {
+	for _, fleetID := range fleetIDs {
+		rank, err := p.rankIn(ctx, fleetID)
+		if err != nil {
+			return err
+		}
+		if rank < roleRanks[role] {
+			return fmt.Errorf("%w: %s requires role %s", fleetErrors.ErrForbidden, fleetID, role)
+		}
+	}
+	return nil
+}
+
+func (p permissionChecker) ListFleets(ctx context.Context) ([]model.Fleet, error) {
+	fleets, err := p.next.ListFleets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := caller.FromContext(ctx)
+	if isUnrestricted(info) {
+		return fleets, nil
+	}
+
+	// only list the fleets the caller has any role in (always non-nil to be serialized as an empty array)
+	roles, err := p.database.GetRoles(ctx, info.Actor)
+	if err != nil {
+		return nil, err
+	}
+	visibleFleets := make([]model.Fleet, 0, len(fleets))
+	for _, fleet := range fleets {
+		if _, ok := roles[fleet.FleetID]; ok || info.Manages(fleet.FleetID) {
+			visibleFleets = append(visibleFleets, fleet)
+		}
+	}
+	return visibleFleets, nil
+}
+
+func (p permissionChecker) CreateFleet(ctx context.Context, fleet model.FleetCreation) (*model.Fleet, error) {
+	// roles cannot be granted in a fleet before it exists, so only the token of the caller can permit this
+	if err := p.require(ctx, model.RoleManager, fleet.FleetID); err != nil {
+		return nil, err
+	}
+	return p.next.CreateFleet(ctx, fleet)
+}
+
+func (p permissionChecker) GetFleet(ctx context.Context, fleetID model.FleetID) (*model.Fleet, error) {
+	if err := p.require(ctx, model.RoleViewer, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetFleet(ctx, fleetID)
+}
+
+func (p permissionChecker) UpdateFleet(ctx context.Context, fleetID model.FleetID,
+	update model.FleetUpdate) (*model.Fleet, error) {
+
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.UpdateFleet(ctx, fleetID, update)
+}
+
+func (p permissionChecker) DeleteFleet(ctx context.Context, fleetID model.FleetID) error {
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return err
+	}
+	return p.next.DeleteFleet(ctx, fleetID)
+}
+
+func (p permissionChecker) GetFleetHistory(ctx context.Context, fleetID model.FleetID,
+	query model.HistoryQuery) (*model.HistoryPage, error) {
+
+	if err := p.require(ctx, model.RoleViewer, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetFleetHistory(ctx, fleetID, query)
+}
+
+func (p permissionChecker) GetCarsInFleet(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.CarPage, error) {
+
+	if err := p.require(ctx, model.RoleViewer, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetCarsInFleet(ctx, fleetID, query)
+}
+
+func (p permissionChecker) GetCarsInFleetTolerant(ctx context.Context, fleetID model.FleetID,
+	query model.CarQuery) (*model.FleetOverview, error) {
+
+	if err := p.require(ctx, model.RoleViewer, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetCarsInFleetTolerant(ctx, fleetID, query)
+}
+
+func (p permissionChecker) RemoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) error {
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return err
+	}
+	return p.next.RemoveCar(ctx, fleetID, vin)
+}
+
+func (p permissionChecker) GetCar(ctx context.Context, fleetID model.FleetID, vin model.Vin) (*model.Car, error) {
+	if err := p.require(ctx, model.RoleViewer, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetCar(ctx, fleetID, vin)
+}
+
+func (p permissionChecker) AddCarToFleet(ctx context.Context, fleetID model.FleetID,
+	vin model.Vin) (*model.CarBase, error) {
+
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.AddCarToFleet(ctx, fleetID, vin)
+}
+
+func (p permissionChecker) CreateCarInFleet(ctx context.Context, fleetID model.FleetID,
+	car model.Car) (*model.CarBase, error) {
+
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.CreateCarInFleet(ctx, fleetID, car)
+}
+
+func (p permissionChecker) AddCarsToFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.AddCarsToFleet(ctx, fleetID, vins)
+}
+
+func (p permissionChecker) RemoveCarsFromFleet(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (*model.BatchResult, error) {
+
+	if err := p.require(ctx, model.RoleManager, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.RemoveCarsFromFleet(ctx, fleetID, vins)
+}
+
+func (p permissionChecker) MoveCar(ctx context.Context, fleetID model.FleetID, vin model.Vin,
+	targetFleetID model.FleetID) error {
+
+	if err := p.require(ctx, model.RoleManager, fleetID, targetFleetID); err != nil {
+		return err
+	}
+	return p.next.MoveCar(ctx, fleetID, vin, targetFleetID)
+}
+
+func (p permissionChecker) DecommissionCar(ctx context.Context, vin model.Vin, reason string) error {
+	// the car may be assigned to any fleet (or none), so only callers with the admin role in every fleet may retire it
+	if !isUnrestricted(caller.FromContext(ctx)) {
+		return fmt.Errorf("%w: decommissioning %s requires the global admin role", fleetErrors.ErrForbidden, vin)
+	}
+	return p.next.DecommissionCar(ctx, vin, reason)
+}
+
+func (p permissionChecker) ReconcileFleets(ctx context.Context,
+	dryRun bool) (*model.ReconciliationReport, error) {
+
+	// all fleets are checked (and possibly changed), so only callers with the admin role in every fleet may do so
+	if !isUnrestricted(caller.FromContext(ctx)) {
+		return nil, fmt.Errorf("%w: reconciling the fleets requires the global admin role", fleetErrors.ErrForbidden)
+	}
+	return p.next.ReconcileFleets(ctx, dryRun)
+}
+
+func (p permissionChecker) GetMembers(ctx context.Context, fleetID model.FleetID) ([]model.Membership, error) {
+	if err := p.require(ctx, model.RoleAdmin, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GetMembers(ctx, fleetID)
+}
+
+func (p permissionChecker) GrantRole(ctx context.Context, fleetID model.FleetID, subject model.Subject,
+	role model.Role) (*model.Membership, error) {
+
+	if err := p.require(ctx, model.RoleAdmin, fleetID); err != nil {
+		return nil, err
+	}
+	return p.next.GrantRole(ctx, fleetID, subject, role)
+}
+
+func (p permissionChecker) RevokeRole(ctx context.Context, fleetID model.FleetID, subject model.Subject) error {
+	if err := p.require(ctx, model.RoleAdmin, fleetID); err != nil {
+		return err
+	}
+	return p.next.RevokeRole(ctx, fleetID, subject)
+}
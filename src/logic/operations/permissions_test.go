@@ -0,0 +1,242 @@
+package operations
+
+import (
+	"PFleetManagement/logic/caller"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"PFleetManagement/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func withCaller(info caller.Info) context.Context {
+	return caller.WithInfo(context.Background(), info)
+}
+
+func rolePtr(role model.Role) *model.Role {
+	return &role
+}
+
+func TestPermissionChecker_unrestricted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	// requests which are deliberately not authenticated are not restricted and do not look up any role
+	ctx := withCaller(caller.Info{Unrestricted: true})
+	mockOperations.EXPECT().RevokeRole(ctx, "jJd9jb8I", "alice").Return(nil)
+
+	err := NewPermissionChecker(mockOperations, mockDB).RevokeRole(ctx, "jJd9jb8I", "alice")
+
+	assert.Nil(t, err)
+}
+
+func TestPermissionChecker_anonymous(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	// callers without a token are not permitted anything unless they are explicitly unrestricted
+	ctx := withCaller(caller.Info{RequestID: "d0a4aa11"})
+	checker := NewPermissionChecker(mockOperations, mockDB)
+
+	_, err := checker.GetFleet(ctx, "jJd9jb8I")
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+
+	err = checker.DeleteFleet(ctx, "jJd9jb8I")
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+
+	_, err = checker.ReconcileFleets(ctx, false)
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+}
+
+func TestPermissionChecker_globalAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{}, Admin: true})
+	members := []model.Membership{{Role: model.RoleViewer, Subject: "bob"}}
+	mockOperations.EXPECT().GetMembers(ctx, "jJd9jb8I").Return(members, nil)
+
+	result, err := NewPermissionChecker(mockOperations, mockDB).GetMembers(ctx, "jJd9jb8I")
+
+	assert.Nil(t, err)
+	assert.Equal(t, members, result)
+}
+
+func TestPermissionChecker_grantedRole(t *testing.T) {
+	tests := map[string]struct {
+		role    *model.Role
+		managed bool
+		allowed map[string]bool
+	}{
+		"none":             {allowed: map[string]bool{"read": false, "write": false, "admin": false}},
+		"viewer":           {role: rolePtr(model.RoleViewer), allowed: map[string]bool{"read": true, "write": false, "admin": false}},
+		"manager":          {role: rolePtr(model.RoleManager), allowed: map[string]bool{"read": true, "write": true, "admin": false}},
+		"admin":            {role: rolePtr(model.RoleAdmin), allowed: map[string]bool{"read": true, "write": true, "admin": true}},
+		"managedByToken":   {managed: true, allowed: map[string]bool{"read": true, "write": true, "admin": false}},
+		"managedAndViewer": {role: rolePtr(model.RoleViewer), managed: true, allowed: map[string]bool{"read": true, "write": true, "admin": false}},
+		"managedAndAdmin":  {role: rolePtr(model.RoleAdmin), managed: true, allowed: map[string]bool{"read": true, "write": true, "admin": true}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockFleetDB(ctrl)
+			mockOperations := mocks.NewMockIOperations(ctrl)
+			checker := NewPermissionChecker(mockOperations, mockDB)
+
+			info := caller.Info{Actor: "alice", Fleets: []model.FleetID{}}
+			if test.managed {
+				info.Fleets = []model.FleetID{"jJd9jb8I"}
+			}
+			ctx := withCaller(info)
+
+			mockDB.EXPECT().GetRole(ctx, "jJd9jb8I", "alice").Return(test.role, nil).Times(3)
+			if test.allowed["read"] {
+				mockOperations.EXPECT().GetCar(ctx, "jJd9jb8I", modelCar1.Vin).Return(&modelCar1, nil)
+			}
+			if test.allowed["write"] {
+				mockOperations.EXPECT().RemoveCar(ctx, "jJd9jb8I", modelCar1.Vin).Return(nil)
+			}
+			if test.allowed["admin"] {
+				mockOperations.EXPECT().GrantRole(ctx, "jJd9jb8I", "bob", model.RoleViewer).
+					Return(&model.Membership{Role: model.RoleViewer, Subject: "bob"}, nil)
+			}
+
+			_, readErr := checker.GetCar(ctx, "jJd9jb8I", modelCar1.Vin)
+			writeErr := checker.RemoveCar(ctx, "jJd9jb8I", modelCar1.Vin)
+			_, adminErr := checker.GrantRole(ctx, "jJd9jb8I", "bob", model.RoleViewer)
+
+			for operation, err := range map[string]error{"read": readErr, "write": writeErr, "admin": adminErr} {
+				if test.allowed[operation] {
+					assert.Nil(t, err, operation)
+				} else {
+					assert.ErrorIs(t, err, fleetErrors.ErrForbidden, operation)
+				}
+			}
+		})
+	}
+}
+
+func TestPermissionChecker_MoveCar_targetFleetNotManaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{"jJd9jb8I"}})
+	mockDB.EXPECT().GetRole(ctx, "jJd9jb8I", "alice").Return(nil, nil)
+	mockDB.EXPECT().GetRole(ctx, "xk48jpgz", "alice").Return(rolePtr(model.RoleViewer), nil)
+
+	err := NewPermissionChecker(mockOperations, mockDB).MoveCar(ctx, "jJd9jb8I", modelCar1.Vin, "xk48jpgz")
+
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+}
+
+func TestPermissionChecker_databaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	dbError := errors.New("database error")
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{}})
+	mockDB.EXPECT().GetRole(ctx, "jJd9jb8I", "alice").Return(nil, dbError)
+
+	_, err := NewPermissionChecker(mockOperations, mockDB).GetFleet(ctx, "jJd9jb8I")
+
+	assert.ErrorIs(t, err, dbError)
+}
+
+func TestPermissionChecker_ListFleets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{"jJd9jb8I"}})
+	fleets := []model.Fleet{{FleetID: "jJd9jb8I"}, {FleetID: "xk48jpgz"}, {FleetID: "P6s3SOEJ"}}
+	mockOperations.EXPECT().ListFleets(ctx).Return(fleets, nil)
+	mockDB.EXPECT().GetRoles(ctx, "alice").Return(map[model.FleetID]model.Role{"P6s3SOEJ": model.RoleViewer}, nil)
+
+	result, err := NewPermissionChecker(mockOperations, mockDB).ListFleets(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.Fleet{{FleetID: "jJd9jb8I"}, {FleetID: "P6s3SOEJ"}}, result)
+}
+
+func TestPermissionChecker_CreateFleet_unmanagedFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{"xk48jpgz"}})
+	mockDB.EXPECT().GetRole(ctx, "jJd9jb8I", "alice").Return(nil, nil)
+
+	_, err := NewPermissionChecker(mockOperations, mockDB).
+		CreateFleet(ctx, model.FleetCreation{FleetID: "jJd9jb8I", Name: "Depot"})
+
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+}
+
+func TestPermissionChecker_DecommissionCar_fleetAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	// administering the fleet of the car is not sufficient as the car is removed from every fleet
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{"jJd9jb8I"}})
+
+	err := NewPermissionChecker(mockOperations, mockDB).DecommissionCar(ctx, modelCar1.Vin, "total loss")
+
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+}
+
+func TestPermissionChecker_DecommissionCar_globalAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{}, Admin: true})
+	mockOperations.EXPECT().DecommissionCar(ctx, modelCar1.Vin, "total loss").Return(nil)
+
+	err := NewPermissionChecker(mockOperations, mockDB).DecommissionCar(ctx, modelCar1.Vin, "total loss")
+
+	assert.Nil(t, err)
+}
+
+func TestPermissionChecker_ReconcileFleets_fleetAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockFleetDB(ctrl)
+	mockOperations := mocks.NewMockIOperations(ctrl)
+
+	ctx := withCaller(caller.Info{Actor: "alice", Fleets: []model.FleetID{"jJd9jb8I"}})
+
+	_, err := NewPermissionChecker(mockOperations, mockDB).ReconcileFleets(ctx, true)
+
+	assert.ErrorIs(t, err, fleetErrors.ErrForbidden)
+}
@@ -0,0 +1,148 @@
+package operations
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix is prepended to the offset encoded in a cursor to detect cursors which were not issued by this service
+const cursorPrefix = "offset:"
+
+// encodeCursor creates an opaque cursor pointing to the given offset in a list of cars
+func encodeCursor(offset int) *model.Cursor {
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+	return &cursor
+}
+
+// decodeCursor reads the offset from the given cursor. A nil cursor points to the start of the list.
+func decodeCursor(cursor *model.Cursor) (int, error) {
+	if cursor == nil {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, fmt.Errorf("%w: %s", fleetErrors.ErrInvalidCursor, *cursor)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: %s", fleetErrors.ErrInvalidCursor, *cursor)
+	}
+
+	return offset, nil
+}
+
+// isFiltered checks whether the query restricts the cars by their data (i.e. not only by their position)
+func isFiltered(query model.CarQuery) bool {
+	return query.Brand != nil || query.Model != nil || query.ProductionDateFrom != nil || query.ProductionDateTo != nil
+}
+
+// databaseVinOrder determines whether the query can be paged by the database, i.e. whether the page only depends on
+// the VINs (and not on the car data from the Car service). If so, the order of the VINs is returned.
+func databaseVinOrder(query model.CarQuery) (database.VinOrder, bool) {
+	if query.Limit == nil || isFiltered(query) {
+		return 0, false
+	}
+
+	if query.Sort == nil {
+		return database.VinOrderAssigned, true
+	}
+	switch *query.Sort {
+	case model.CarSortVin:
+		return database.VinOrderAscending, true
+	case model.CarSortVinDesc:
+		return database.VinOrderDescending, true
+	}
+
+	return 0, false
+}
+
+// matches checks whether the given car satisfies all filters of the query
+func matches(car *model.CarBase, query model.CarQuery) bool {
+	if query.Brand != nil && car.Brand != *query.Brand {
+		return false
+	}
+	if query.Model != nil && car.Model != *query.Model {
+		return false
+	}
+	if query.ProductionDateFrom != nil && car.ProductionDate.Before(query.ProductionDateFrom.Time) {
+		return false
+	}
+	if query.ProductionDateTo != nil && car.ProductionDate.After(query.ProductionDateTo.Time) {
+		return false
+	}
+	return true
+}
+
+// filterCars returns the cars which satisfy all filters of the query in their original order
+func filterCars(cars []model.CarBase, query model.CarQuery) []model.CarBase {
+	filtered := make([]model.CarBase, 0, len(cars))
+	for index := range cars {
+		if matches(&cars[index], query) {
+			filtered = append(filtered, cars[index])
+		}
+	}
+	return filtered
+}
+
+// sortCars sorts the cars in place by the property given in the query. Cars with equal properties are ordered by
+// their VIN so that the order (and thus the pages) are stable. Without a sort property, the order is not changed.
+func sortCars(cars []model.CarBase, query model.CarQuery) {
+	if query.Sort == nil {
+		return
+	}
+
+	sortParam := *query.Sort
+	descending := strings.HasPrefix(string(sortParam), "-")
+	property := model.CarSortParam(strings.TrimPrefix(string(sortParam), "-"))
+
+	compare := func(a, b *model.CarBase) int {
+		switch property {
+		case model.CarSortBrand:
+			return strings.Compare(a.Brand, b.Brand)
+		case model.CarSortModel:
+			return strings.Compare(a.Model, b.Model)
+		case model.CarSortProductionDate:
+			return a.ProductionDate.Compare(b.ProductionDate.Time)
+		}
+		return 0
+	}
+
+	sort.SliceStable(cars, func(i, j int) bool {
+		result := compare(&cars[i], &cars[j])
+		if result == 0 {
+			result = strings.Compare(cars[i].Vin, cars[j].Vin)
+		}
+		if descending {
+			return result > 0
+		}
+		return result < 0
+	})
+}
+
+// paginate returns the cars of the page starting at the given offset with at most limit cars (all if limit is nil)
+// and the cursor of the next page if there are further cars
+func paginate(cars []model.CarBase, offset int, limit *int) ([]model.CarBase, *model.Cursor) {
+	if offset >= len(cars) {
+		return []model.CarBase{}, nil
+	}
+
+	end := len(cars)
+	if limit != nil && offset+*limit < end {
+		end = offset + *limit
+	}
+
+	var nextCursor *model.Cursor
+	if end < len(cars) {
+		nextCursor = encodeCursor(end)
+	}
+
+	return cars[offset:end], nextCursor
+}
@@ -0,0 +1,124 @@
+package operations
+
+import (
+	"PFleetManagement/infrastructure/dcar"
+	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/logic/model"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+func (o operations) ReconcileFleets(ctx context.Context, dryRun bool) (*model.ReconciliationReport, error) {
+	// --- Car service interaction ---
+	// listing the known cars once is cheaper than requesting every assigned car individually
+	knownVins, err := o.listCarsFromDomain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// --- database interaction ---
+	fleets, err := o.database.ListFleets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ReconciliationReport{
+		DanglingCars: []model.DanglingCar{},
+		DryRun:       dryRun,
+	}
+	for _, fleet := range fleets {
+		vins, err := o.database.GetCarsForFleet(ctx, fleet.FleetID, nil)
+		if errors.Is(err, fleetErrors.ErrFleetNotFound) {
+			// the fleet has been deleted in the meantime, so its cars are not dangling any longer
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.CheckedFleets++
+		report.CheckedCars += len(vins)
+
+		danglingVins, err := o.findDanglingCars(ctx, vins, knownVins)
+		if err != nil {
+			return nil, err
+		}
+		if len(danglingVins) == 0 {
+			continue
+		}
+
+		pruned := map[model.Vin]bool{}
+		if !dryRun {
+			if pruned, err = o.pruneCars(ctx, fleet.FleetID, danglingVins); err != nil {
+				return nil, err
+			}
+		}
+		for _, vin := range danglingVins {
+			report.DanglingCars = append(report.DanglingCars, model.DanglingCar{
+				FleetID: fleet.FleetID,
+				Pruned:  pruned[vin],
+				Vin:     vin,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// findDanglingCars returns those of the given VINs which are not known to the Car service. The cars which are not
+// contained in the given list of known VINs are requested individually to confirm that they do not exist, as they
+// may have been created after the list was read.
+func (o operations) findDanglingCars(ctx context.Context, vins []model.Vin,
+	knownVins map[model.Vin]bool) ([]model.Vin, error) {
+
+	var dangling []model.Vin
+	for _, vin := range vins {
+		if knownVins[vin] {
+			continue
+		}
+
+		// --- Car service interaction ---
+		// (the static data is not sufficient, as cars deleted in the meantime may still be cached with it)
+		carResponse, err := o.carClient.GetCarWithResponse(ctx, vin)
+		if err != nil {
+			return nil, err
+		}
+
+		switch statusCode := carResponse.StatusCode(); {
+		case carResponse.JSON200 != nil:
+			continue
+		case statusCode != http.StatusNotFound:
+			return nil, fmt.Errorf("%w: unknown error (domain code %d)", fleetErrors.ErrDomainAssertion, statusCode)
+		}
+		dangling = append(dangling, vin)
+	}
+	return dangling, nil
+}
+
+// pruneCars removes the given cars from the given fleet and returns which of them have actually been removed
+func (o operations) pruneCars(ctx context.Context, fleetID model.FleetID,
+	vins []model.Vin) (map[model.Vin]bool, error) {
+
+	// --- database interaction ---
+	removedVins, err := o.database.RemoveCarsFromFleet(ctx, fleetID, vins)
+	if errors.Is(err, fleetErrors.ErrFleetNotFound) {
+		// the fleet has been deleted in the meantime, so its cars are not assigned to it any longer
+		return map[model.Vin]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[model.Vin]bool, len(removedVins))
+	invalidator, caching := o.carClient.(dcar.CacheInvalidator)
+	for _, vin := range removedVins {
+		removed[vin] = true
+
+		// the (outdated) data of the car is not required any longer if the Car service client caches it
+		if caching {
+			invalidator.Invalidate(vin)
+		}
+	}
+	return removed, nil
+}
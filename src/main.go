@@ -3,81 +3,251 @@ package main
 import (
 	"PFleetManagement/api"
 	"PFleetManagement/environment"
+	"PFleetManagement/infrastructure/auth"
 	"PFleetManagement/infrastructure/database"
 	"PFleetManagement/infrastructure/dcar"
+	"PFleetManagement/infrastructure/health"
+	"PFleetManagement/infrastructure/logging"
+	"PFleetManagement/infrastructure/metrics"
 	rentalManagement "PFleetManagement/infrastructure/rentalmanagement"
-	"PFleetManagement/logic/fleetErrors"
+	"PFleetManagement/infrastructure/resilience"
+	"PFleetManagement/infrastructure/tracing"
 	"PFleetManagement/logic/operations"
 	"context"
 	"errors"
 	"fmt"
+	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 // newApp allows production as well as testing to create a new Echo instance for the API.
 // Configuration values are read from the environment with environment.GetEnvironment().
-func newApp(fleetDb database.FleetDB) (*echo.Echo, error) {
+// The app, its database and its clients are instrumented with the given metrics.Metrics and traced with the global
+// tracer provider (see tracing.Setup).
+func newApp(fleetDb database.FleetDB, appMetrics *metrics.Metrics) (*echo.Echo, error) {
 	e := echo.New()
 	e.HTTPErrorHandler = api.FleetErrorHandler
 
+	// trace, record and log all requests, including those rejected by the following middlewares
+	e.Use(tracing.Middleware)
+	e.Use(appMetrics.Middleware)
+	e.Use(logging.AccessLog(os.Stdout))
+
+	appMetrics.RegisterFleetSizes(fleetDb)
+	fleetDb = tracing.InstrumentFleetDB(appMetrics.InstrumentFleetDB(fleetDb))
+
 	allowOrigins := environment.GetEnvironment().GetAllowOrigins()
 
 	if len(allowOrigins) > 0 {
 		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 			AllowOrigins: allowOrigins,
+			// allow browsers to read the cursor of the next page of the fleet overview
+			ExposeHeaders: []string{api.NextCursorHeader},
 		}))
 	}
 
-	// validate incoming requests against the OpenAPI spec
-	err := api.AddOpenApiValidationMiddleware(e)
+	// identify every request (reusing the ID given by the client, if any)
+	e.Use(middleware.RequestID())
+
+	requestTimeout := environment.GetEnvironment().GetRequestTimeout()
+
+	authenticate, err := newAuthenticationFunc(&http.Client{Timeout: requestTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	// validate incoming requests against the OpenAPI spec, including their bearer tokens
+	err = api.AddOpenApiValidationMiddleware(e, authenticate, health.LivenessPath, health.ReadinessPath)
 	if err != nil {
 		return nil, err
 	}
 
-	err = fleetDb.AddFleet(context.TODO(), "xk48jpgz") // TODO manage fleets correctly
-	if err != nil && !errors.Is(err, fleetErrors.ErrFleetAlreadyExists) {
+	// attribute changes to the (authenticated) caller
+	e.Use(api.NewCallerMiddleware(authenticate == nil))
+
+	operationsInstance, err := newOperations(fleetDb, appMetrics)
+	if err != nil {
 		return nil, err
 	}
+	controllerInstance := api.NewController(
+		tracing.InstrumentOperations(operations.NewPermissionChecker(operationsInstance, fleetDb)))
+
+	api.RegisterHandlers(e, controllerInstance)
+
+	// the downstream services are probed without retries, so that the probe reflects their current state
+	readinessTimeout := environment.GetEnvironment().GetReadinessTimeout()
+	probeClient := &http.Client{Timeout: readinessTimeout}
+	checker := health.NewChecker(readinessTimeout)
+	checker.Add("database", fleetDb.Ping)
+	checker.Add("car", health.ServerCheck(probeClient, environment.GetEnvironment().GetCarServerUrl()))
+	checker.Add("rentalManagement",
+		health.ServerCheck(probeClient, environment.GetEnvironment().GetRentalServerUrl()))
+	checker.Register(e)
 
+	return e, nil
+}
+
+// newOperations creates the operations on the given (instrumented) database with clients of the downstream services
+// which are configured with environment.GetEnvironment() and instrumented with the given metrics.Metrics.
+func newOperations(fleetDb database.FleetDB, appMetrics *metrics.Metrics) (operations.IOperations, error) {
 	requestTimeout := environment.GetEnvironment().GetRequestTimeout()
 
+	// requests to the downstream services are retried and stopped if a service is unavailable
+	httpClient := resilience.NewDoer(&http.Client{Timeout: requestTimeout}, resilience.Config{
+		MaxRetries:       environment.GetEnvironment().GetRequestRetries(),
+		BaseBackoff:      environment.GetEnvironment().GetRequestRetryBackoff(),
+		MaxBackoff:       environment.GetEnvironment().GetRequestRetryMaxBackoff(),
+		FailureThreshold: environment.GetEnvironment().GetCircuitBreakerThreshold(),
+		OpenDuration:     environment.GetEnvironment().GetCircuitBreakerOpenDuration(),
+	})
+
 	dcarClient, err := dcar.NewClientWithResponses(
 		environment.GetEnvironment().GetCarServerUrl(),
-		dcar.WithHTTPClient(&http.Client{Timeout: requestTimeout}),
+		dcar.WithHTTPClient(tracing.InstrumentDoer("car", appMetrics.InstrumentDoer("car", httpClient))),
+		dcar.WithRequestEditorFn(tracing.InjectTraceContext),
+		dcar.WithRequestEditorFn(logging.ForwardRequestID),
 	)
 
+	if err != nil {
+		return nil, err
+	}
+
+	// concurrent requests for the same car share one request to the Car service
+	coalescingCarClient := dcar.NewCoalescingClient(dcarClient)
+	appMetrics.RegisterCoalescing("car", coalescingCarClient.Stats)
+
+	// cache the data of cars unless disabled by a zero TTL; only cache misses are coalesced
+	var carClient dcar.ClientWithResponsesInterface = coalescingCarClient
+	if staticTTL := environment.GetEnvironment().GetCarCacheStaticTTL(); staticTTL > 0 {
+		cachingClient := dcar.NewCachingClient(coalescingCarClient, dcar.CacheConfig{
+			StaticTTL:  staticTTL,
+			DynamicTTL: environment.GetEnvironment().GetCarCacheDynamicTTL(),
+			MaxEntries: environment.GetEnvironment().GetCarCacheSize(),
+		})
+		appMetrics.RegisterCarCache(cachingClient.Stats)
+		carClient = cachingClient
+	}
+
 	rmClient, err := rentalManagement.NewClientWithResponses(
 		environment.GetEnvironment().GetRentalServerUrl(),
-		rentalManagement.WithHTTPClient(&http.Client{Timeout: requestTimeout}),
+		rentalManagement.WithHTTPClient(
+			tracing.InstrumentDoer("rentalManagement", appMetrics.InstrumentDoer("rentalManagement", httpClient))),
+		rentalManagement.WithRequestEditorFn(tracing.InjectTraceContext),
+		rentalManagement.WithRequestEditorFn(logging.ForwardRequestID),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	operationsInstance := operations.NewOperations(fleetDb, dcarClient, rmClient)
-	controllerInstance := api.NewController(operationsInstance)
+	// concurrent requests for the next rental of the same car share one request to the RentalManagement service
+	coalescingRmClient := rentalManagement.NewCoalescingClient(rmClient)
+	appMetrics.RegisterCoalescing("rentalManagement", coalescingRmClient.Stats)
 
-	api.RegisterHandlers(e, controllerInstance)
+	// the VINs of batch operations are checked against the format of the spec
+	vinPattern, err := api.VinPattern()
+	if err != nil {
+		return nil, err
+	}
 
-	return e, nil
+	return operations.NewOperations(fleetDb, carClient, coalescingRmClient,
+		operations.WithCarRequestConcurrency(environment.GetEnvironment().GetCarRequestConcurrency()),
+		operations.WithVinPattern(vinPattern)), nil
+}
+
+// newAuthenticationFunc creates the function verifying the bearer tokens of requests with the configured key set,
+// which is loaded with the given client if it is given by a URL. Returns nil if no key set is configured and
+// unauthenticated access is allowed (see environment.Environment.IsUnauthenticatedAccessAllowed), and fails if it is
+// not, so that a missing configuration never opens all fleets to everyone.
+func newAuthenticationFunc(client *http.Client) (openapi3filter.AuthenticationFunc, error) {
+	keySetSource := environment.GetEnvironment().GetAuthKeySet()
+	if keySetSource == "" {
+		if !environment.GetEnvironment().IsUnauthenticatedAccessAllowed() {
+			return nil, errors.New("no key set configured (FM_AUTH_JWKS), requests cannot be authenticated")
+		}
+		log.Println("WARNING: no key set configured, requests are not authenticated")
+		return nil, nil
+	}
+
+	keySet, err := auth.LoadKeySet(context.Background(), keySetSource, client)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := auth.NewVerifier(keySet, auth.Config{
+		Issuer:      environment.GetEnvironment().GetAuthIssuer(),
+		Audience:    environment.GetEnvironment().GetAuthAudience(),
+		FleetsClaim: environment.GetEnvironment().GetAuthFleetsClaim(),
+		RolesClaim:  environment.GetEnvironment().GetAuthRolesClaim(),
+	})
+	return api.NewAuthenticationFunc(verifier), nil
 }
 
 func main() {
+	// SIGINT and SIGTERM start the graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	var fleetDb database.FleetDB
 	fleetDb, err := database.OpenDatabase(environment.GetEnvironment())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	var e *echo.Echo
-	e, err = newApp(fleetDb)
+	shutdownTracing, err := tracing.Setup(context.Background(), environment.GetEnvironment().GetTracingExporter())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", environment.GetEnvironment().GetAppExposePort())))
+	appMetrics := metrics.New()
+
+	// instead of serving the API, the fleets can be reconciled with the Car service once (e.g. by a cron job)
+	if len(os.Args) > 1 && os.Args[1] == reconcileCommand {
+		err = reconcile(ctx, fleetDb, appMetrics, os.Args[2:])
+	} else {
+		err = serve(ctx, fleetDb, appMetrics)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+
+	// the dependencies are released only after the in-flight requests are finished (or given up)
+	if cleanUpErr := fleetDb.CleanUpDatabase(); cleanUpErr != nil {
+		log.Println(cleanUpErr)
+	}
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		log.Println(shutdownErr)
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// serve serves the API and the metrics until the given context is done
+func serve(ctx context.Context, fleetDb database.FleetDB, appMetrics *metrics.Metrics) error {
+	e, err := newApp(fleetDb, appMetrics)
+	if err != nil {
+		return err
+	}
+
+	// the metrics are exposed on a separate port so that they are not publicly accessible with the API
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", appMetrics.Handler())
+	metricsServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", environment.GetEnvironment().GetMetricsExposePort()),
+		Handler: metricsMux,
+	}
+
+	// a failing metrics server shuts down the app gracefully instead of exiting immediately
+	return serveAlongside(ctx, metricsServer, func(ctx context.Context) error {
+		return serveUntilDone(ctx, e, fmt.Sprintf(":%d", environment.GetEnvironment().GetAppExposePort()),
+			environment.GetEnvironment().GetShutdownTimeout())
+	})
 }
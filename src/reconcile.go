@@ -0,0 +1,54 @@
+package main
+
+import (
+	"PFleetManagement/infrastructure/database"
+	"PFleetManagement/infrastructure/metrics"
+	"PFleetManagement/infrastructure/tracing"
+	"PFleetManagement/logic/operations"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// reconcileCommand is the subcommand which reconciles the fleets with the Car service instead of serving the API
+const reconcileCommand = "reconcile"
+
+// reconcile checks the cars of all fleets in the given database against the Car service as configured by the given
+// command line arguments and prints the report to stdout
+func reconcile(ctx context.Context, fleetDb database.FleetDB, appMetrics *metrics.Metrics, args []string) error {
+	fleetDb = tracing.InstrumentFleetDB(appMetrics.InstrumentFleetDB(fleetDb))
+
+	operationsInstance, err := newOperations(fleetDb, appMetrics)
+	if err != nil {
+		return err
+	}
+
+	// the command is run by operators with direct access to the database, so no permissions are checked
+	return runReconcile(ctx, tracing.InstrumentOperations(operationsInstance), args, os.Stdout)
+}
+
+// runReconcile parses the given command line arguments of the reconcile command, reconciles the fleets with the
+// given operations and writes the report as JSON to the given output
+func runReconcile(ctx context.Context, ops operations.IOperations, args []string, output io.Writer) error {
+	flags := flag.NewFlagSet(reconcileCommand, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", true,
+		"only report the cars which are unknown to the Car service instead of removing them from their fleets")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments of %s: %v", reconcileCommand, flags.Args())
+	}
+
+	report, err := ops.ReconcileFleets(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"PFleetManagement/logic/model"
+	"PFleetManagement/mocks"
+	"bytes"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRunReconcile_dryRunByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	mockOperations.EXPECT().ReconcileFleets(ctx, true).Return(&model.ReconciliationReport{
+		CheckedCars:   1,
+		CheckedFleets: 1,
+		DanglingCars:  []model.DanglingCar{{FleetID: "jJd9jb8I", Vin: "WVWAA71K08W201030"}},
+		DryRun:        true,
+	}, nil)
+
+	var output bytes.Buffer
+	err := runReconcile(ctx, mockOperations, []string{}, &output)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"checkedCars": 1,
+		"checkedFleets": 1,
+		"danglingCars": [{"fleetId": "jJd9jb8I", "vin": "WVWAA71K08W201030", "pruned": false}],
+		"dryRun": true
+	}`, output.String())
+}
+
+func TestRunReconcile_prune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockOperations := mocks.NewMockIOperations(ctrl)
+	mockOperations.EXPECT().ReconcileFleets(ctx, false).Return(&model.ReconciliationReport{}, nil)
+
+	err := runReconcile(ctx, mockOperations, []string{"-dry-run=false"}, &bytes.Buffer{})
+
+	assert.Nil(t, err)
+}
+
+func TestRunReconcile_unexpectedArguments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the operations are not called at all
+	err := runReconcile(context.Background(), mocks.NewMockIOperations(ctrl), []string{"now"}, &bytes.Buffer{})
+
+	assert.NotNil(t, err)
+}
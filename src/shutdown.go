@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// serveUntilDone serves the given app at the given address until the given context is done (e.g. on SIGTERM). Then
+// it stops accepting connections and waits up to the given timeout for the in-flight requests to finish. Fails if
+// the app cannot be started or the requests are not finished in time.
+func serveUntilDone(ctx context.Context, e *echo.Echo, address string, drainTimeout time.Duration) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- e.Start(address)
+	}()
+
+	select {
+	case err := <-served:
+		// the server stopped before the shutdown, so it could not be started
+		return err
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := e.Shutdown(drainCtx); err != nil {
+		return err
+	}
+
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveAlongside serves the given auxiliary server (e.g. for the metrics) while running the given function (e.g.
+// serving the app until the given context is done). If the server fails, the context of the function is canceled, so
+// that it can finish gracefully, and the error of the server is returned as well. The server is closed as soon as the
+// function returns.
+func serveAlongside(ctx context.Context, server *http.Server, run func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	served := make(chan error, 1)
+	go func() {
+		err := server.ListenAndServe()
+		if !errors.Is(err, http.ErrServerClosed) {
+			cancel()
+		}
+		served <- err
+	}()
+
+	err := run(ctx)
+	_ = server.Close()
+
+	if serverErr := <-served; !errors.Is(serverErr, http.ErrServerClosed) {
+		return errors.Join(err, serverErr)
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newSlowApp creates an app listening on a random local port whose only endpoint signals on started when a request
+// is being handled and responds after the given delay
+func newSlowApp(t *testing.T, delay time.Duration) (*echo.Echo, chan struct{}) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Listener = listener
+
+	started := make(chan struct{}, 1)
+	e.GET("/slow", func(ctx echo.Context) error {
+		started <- struct{}{}
+		time.Sleep(delay)
+		return ctx.NoContent(http.StatusOK)
+	})
+	return e, started
+}
+
+func TestServeUntilDone_drainsRequests(t *testing.T) {
+	e, started := newSlowApp(t, 200*time.Millisecond)
+	url := "http://" + e.Listener.Addr().String() + "/slow"
+	ctx, stop := context.WithCancel(context.Background())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serveUntilDone(ctx, e, "", time.Second)
+	}()
+
+	responses := make(chan int, 1)
+	go func() {
+		response, err := http.Get(url)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		_ = response.Body.Close()
+		responses <- response.StatusCode
+	}()
+
+	// shut down while the request is in flight
+	<-started
+	stop()
+
+	assert.Nil(t, <-served)
+	assert.Equal(t, http.StatusOK, <-responses)
+
+	// no further connections are accepted
+	_, err := http.Get(url)
+	assert.NotNil(t, err)
+}
+
+func TestServeUntilDone_drainTimeout(t *testing.T) {
+	e, started := newSlowApp(t, time.Second)
+	url := "http://" + e.Listener.Addr().String() + "/slow"
+	ctx, stop := context.WithCancel(context.Background())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serveUntilDone(ctx, e, "", 50*time.Millisecond)
+	}()
+	go func() {
+		response, err := http.Get(url)
+		if err == nil {
+			_ = response.Body.Close()
+		}
+	}()
+
+	<-started
+	stop()
+
+	// the request takes longer than the deadline
+	assert.ErrorIs(t, <-served, context.DeadlineExceeded)
+}
+
+func TestServeUntilDone_startFailure(t *testing.T) {
+	e := echo.New()
+	e.HideBanner = true
+
+	err := serveUntilDone(context.Background(), e, "invalid address", time.Second)
+
+	assert.NotNil(t, err)
+}
+
+func TestServeAlongside_serverFailure(t *testing.T) {
+	server := &http.Server{Addr: "invalid address"}
+
+	err := serveAlongside(context.Background(), server, func(ctx context.Context) error {
+		// the function is stopped instead of the whole process
+		<-ctx.Done()
+		return nil
+	})
+
+	assert.NotNil(t, err)
+}
+
+func TestServeAlongside_serverClosed(t *testing.T) {
+	server := &http.Server{Addr: "127.0.0.1:0"}
+	runErr := errors.New("run error")
+
+	err := serveAlongside(context.Background(), server, func(ctx context.Context) error {
+		return runErr
+	})
+
+	assert.Equal(t, runErr, err)
+}
@@ -1,6 +1,9 @@
 package testdata
 
-import _ "embed"
+import (
+	"PFleetManagement/logic/model"
+	_ "embed"
+)
 
 const UnknownVin string = "G1YZ23J9P58034278"
 const FleetId string = "xk48jpgz"
@@ -28,5 +31,31 @@ const VinCar2 string = "WVWAA71K08W201031"
 //go:embed exampleFleetOverview.json
 var ExampleFleetOverview string
 
+// ExampleFleetOverviewWithErrors is the tolerant fleet overview of a fleet with the cars VinCar and UnknownVin
+//
+//go:embed exampleFleetOverviewWithErrors.json
+var ExampleFleetOverviewWithErrors string
+
+// CursorSecondCar is the cursor pointing to the second car of a list
+const CursorSecondCar string = "b2Zmc2V0OjE"
+
+// CursorThirdEntry is the cursor pointing to the third entry of a list
+const CursorThirdEntry string = "b2Zmc2V0OjI"
+
 //go:embed exampleRental.json
 var ExampleRental string
+
+//go:embed exampleFleetCreation.json
+var ExampleFleetCreation string
+
+// ExampleFleetMetadata is the fleet described by ExampleFleetCreation
+var ExampleFleetMetadata = model.FleetCreation{
+	FleetID:     FleetId,
+	Name:        "Karlsruhe Central Station",
+	Description: "All cars operated from the depot at Karlsruhe Central Station",
+	Owner:       "KIT Mobility",
+	Labels: map[string]string{
+		"region":     "south",
+		"costCenter": "4711",
+	},
+}